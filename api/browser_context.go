@@ -9,17 +9,23 @@ type BrowserContext interface {
 	AddCookies(cookies goja.Value)
 	AddInitScript(script goja.Value, arg goja.Value) error
 	Browser() Browser
-	ClearCookies()
+	ClearCache() error
+	ClearCookies(opts goja.Value)
 	ClearPermissions()
+	ClearStorage(opts goja.Value) error
 	Close()
-	Cookies() ([]any, error) // TODO: make it []Cookie later on
+	Cookies(urls ...string) ([]*Cookie, error)
 	ExposeBinding(name string, callback goja.Callable, opts goja.Value)
 	ExposeFunction(name string, callback goja.Callable)
+	ExtensionWorkers() []Worker
 	GrantPermissions(permissions []string, opts goja.Value)
 	NewCDPSession() CDPSession
 	NewPage() (Page, error)
+	On(event string, handler func(any) error) error
 	Pages() []Page
-	Route(url goja.Value, handler goja.Callable)
+	Route(url goja.Value, handler RouteHandler)
+	RouteFromHAR(path string, opts goja.Value)
+	ServiceWorkers() []Worker
 	SetDefaultNavigationTimeout(timeout int64)
 	SetDefaultTimeout(timeout int64)
 	SetExtraHTTPHeaders(headers map[string]string) error
@@ -32,7 +38,7 @@ type BrowserContext interface {
 	// - https://github.com/microsoft/playwright/pull/2763
 	SetHTTPCredentials(httpCredentials goja.Value)
 	SetOffline(offline bool)
-	StorageState(opts goja.Value)
-	Unroute(url goja.Value, handler goja.Callable)
+	StorageState(opts goja.Value) (*StorageState, error)
+	Unroute(url goja.Value, handler goja.Value)
 	WaitForEvent(event string, optsOrPredicate goja.Value) any
 }
@@ -9,6 +9,6 @@ type BrowserType interface {
 	Connect(wsEndpoint string) Browser
 	ExecutablePath() string
 	Launch() (_ Browser, browserProcessID int)
-	LaunchPersistentContext(userDataDir string, opts goja.Value) Browser
+	LaunchPersistentContext(userDataDir string, opts goja.Value) (_ BrowserContext, browserProcessID int)
 	Name() string
 }
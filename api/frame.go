@@ -41,7 +41,7 @@ type Frame interface {
 	ParentFrame() Frame
 	Press(selector string, key string, opts goja.Value)
 	SelectOption(selector string, values goja.Value, opts goja.Value) []string
-	SetContent(html string, opts goja.Value)
+	SetContent(html string, opts goja.Value) error
 	SetInputFiles(selector string, files goja.Value, opts goja.Value)
 	Tap(selector string, opts goja.Value)
 	TextContent(selector string, opts goja.Value) string
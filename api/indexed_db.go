@@ -0,0 +1,12 @@
+package api
+
+import "github.com/dop251/goja"
+
+// IndexedDB is the interface of the IndexedDB databases of the origin of a page.
+type IndexedDB interface {
+	Clear(dbName string, storeName string) error
+	Database(dbName string) (*IndexedDBDatabase, error)
+	Databases() ([]string, error)
+	Entries(dbName string, storeName string, opts goja.Value) ([]*IndexedDBEntry, error)
+	Seed(dbName string, storeName string, entries goja.Value, opts goja.Value) error
+}
@@ -17,6 +17,7 @@ type Page interface {
 	DispatchEvent(selector string, typ string, eventInit goja.Value, opts goja.Value)
 	DragAndDrop(source string, target string, opts goja.Value)
 	EmulateMedia(opts goja.Value)
+	EmulateNetworkConditions(conditions goja.Value)
 	EmulateVisionDeficiency(typ string)
 	Evaluate(pageFunc goja.Value, arg ...goja.Value) any
 	EvaluateHandle(pageFunc goja.Value, arg ...goja.Value) (JSHandle, error)
@@ -27,6 +28,7 @@ type Page interface {
 	Frame(frameSelector goja.Value) Frame
 	Frames() []Frame
 	GetAttribute(selector string, name string, opts goja.Value) goja.Value
+	GetIndexedDB() IndexedDB
 	GetKeyboard() Keyboard
 	GetMouse() Mouse
 	GetTouchscreen() Touchscreen
@@ -47,17 +49,20 @@ type Page interface {
 	// Locator creates and returns a new locator for this page (main frame).
 	Locator(selector string, opts goja.Value) Locator
 	MainFrame() Frame
+	Metrics() (map[string]float64, error)
+	On(event string, handler func(any) error) error
 	Opener() Page
 	Pause()
 	Pdf(opts goja.Value) []byte
 	Press(selector string, key string, opts goja.Value)
 	Query(selector string) (ElementHandle, error)
 	QueryAll(selector string) ([]ElementHandle, error)
-	Reload(opts goja.Value) Response
-	Route(url goja.Value, handler goja.Callable)
+	Reload(opts goja.Value) (Response, error)
+	Route(url goja.Value, handler RouteHandler)
+	RouteFromHAR(path string, opts goja.Value)
 	Screenshot(opts goja.Value) goja.ArrayBuffer
 	SelectOption(selector string, values goja.Value, opts goja.Value) []string
-	SetContent(html string, opts goja.Value)
+	SetContent(html string, opts goja.Value) error
 	SetDefaultNavigationTimeout(timeout int64)
 	SetDefaultTimeout(timeout int64)
 	SetExtraHTTPHeaders(headers map[string]string)
@@ -65,10 +70,11 @@ type Page interface {
 	SetViewportSize(viewportSize goja.Value)
 	Tap(selector string, opts goja.Value)
 	TextContent(selector string, opts goja.Value) string
+	ThrottleCPU(rate float64)
 	Title() string
 	Type(selector string, text string, opts goja.Value)
 	Uncheck(selector string, opts goja.Value)
-	Unroute(url goja.Value, handler goja.Callable)
+	Unroute(url goja.Value, handler goja.Value)
 	URL() string
 	Video() Video
 	ViewportSize() map[string]float64
@@ -76,9 +82,27 @@ type Page interface {
 	WaitForFunction(fn, opts goja.Value, args ...goja.Value) (any, error)
 	WaitForLoadState(state string, opts goja.Value)
 	WaitForNavigation(opts goja.Value) (Response, error)
-	WaitForRequest(urlOrPredicate, opts goja.Value) Request
-	WaitForResponse(urlOrPredicate, opts goja.Value) Response
+	WaitForRequest(matcher RequestMatcher, opts goja.Value) (func() (Request, error), error)
+	WaitForResponse(matcher ResponseMatcher, opts goja.Value) (func() (Response, error), error)
 	WaitForSelector(selector string, opts goja.Value) (ElementHandle, error)
 	WaitForTimeout(timeout int64)
 	Workers() []Worker
 }
+
+// RequestMatcher matches the requests waited for by Page.waitForRequest.
+type RequestMatcher struct {
+	// URL is the glob pattern string or the regular expression
+	// that the request URLs must match if Predicate is nil.
+	URL goja.Value
+	// Predicate is called on the event loop with each request.
+	Predicate func(Request) (bool, error)
+}
+
+// ResponseMatcher matches the responses waited for by Page.waitForResponse.
+type ResponseMatcher struct {
+	// URL is the glob pattern string or the regular expression
+	// that the response URLs must match if Predicate is nil.
+	URL goja.Value
+	// Predicate is called on the event loop with each response.
+	Predicate func(Response) (bool, error)
+}
@@ -14,7 +14,7 @@ type Request interface {
 	Method() string
 	PostData() string
 	PostDataBuffer() goja.ArrayBuffer
-	PostDataJSON() string
+	PostDataJSON() goja.Value
 	RedirectedFrom() Request
 	RedirectedTo() Request
 	ResourceType() string
@@ -9,3 +9,11 @@ type Route interface {
 	Fulfill(opts goja.Value)
 	Request() Request
 }
+
+// RouteHandler handles the requests routed by Page.route and BrowserContext.route.
+type RouteHandler struct {
+	// Value is the JS handler, which Unroute compares to remove the route.
+	Value goja.Value
+	// Call is called on the event loop with each routed request.
+	Call func(Route) error
+}
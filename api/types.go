@@ -23,3 +23,61 @@ type Rect struct {
 	Width  float64 `js:"width"`
 	Height float64 `js:"height"`
 }
+
+// Cookie is a browser cookie. URL is only used for adding a cookie,
+// in place of its domain and path.
+type Cookie struct {
+	Name     string  `js:"name" json:"name"`
+	Value    string  `js:"value" json:"value"`
+	URL      string  `js:"url" json:"url,omitempty"`
+	Domain   string  `js:"domain" json:"domain"`
+	Path     string  `js:"path" json:"path"`
+	Expires  float64 `js:"expires" json:"expires"`
+	HTTPOnly bool    `js:"httpOnly" json:"httpOnly"`
+	Secure   bool    `js:"secure" json:"secure"`
+	SameSite string  `js:"sameSite" json:"sameSite,omitempty"`
+}
+
+// StorageState is the cookies and the local storage of a browser context.
+type StorageState struct {
+	Cookies []*Cookie             `js:"cookies" json:"cookies"`
+	Origins []*StorageStateOrigin `js:"origins" json:"origins"`
+}
+
+// StorageStateOrigin is the local storage of an origin.
+type StorageStateOrigin struct {
+	Origin       string              `js:"origin" json:"origin"`
+	LocalStorage []*LocalStorageItem `js:"localStorage" json:"localStorage"`
+}
+
+// LocalStorageItem is a single local storage item.
+type LocalStorageItem struct {
+	Name  string `js:"name" json:"name"`
+	Value string `js:"value" json:"value"`
+}
+
+// IndexedDBDatabase is an IndexedDB database and its object stores.
+type IndexedDBDatabase struct {
+	Name         string                  `js:"name" json:"name"`
+	Version      float64                 `js:"version" json:"version"`
+	ObjectStores []*IndexedDBObjectStore `js:"objectStores" json:"objectStores"`
+}
+
+// IndexedDBObjectStore is an object store of an IndexedDB database.
+// KeyPath is either a string, an array of strings, or nil if the
+// object store uses out-of-line keys.
+type IndexedDBObjectStore struct {
+	Name          string   `js:"name" json:"name"`
+	KeyPath       any      `js:"keyPath" json:"keyPath"`
+	AutoIncrement bool     `js:"autoIncrement" json:"autoIncrement"`
+	Indexes       []string `js:"indexes" json:"indexes"`
+}
+
+// IndexedDBEntry is an entry of an IndexedDB object store. Key is the
+// index key if the entries are read with an index, otherwise it is the
+// same as the primary key.
+type IndexedDBEntry struct {
+	Key        any `js:"key" json:"key"`
+	PrimaryKey any `js:"primaryKey" json:"primaryKey"`
+	Value      any `js:"value" json:"value"`
+}
@@ -0,0 +1,8 @@
+package api
+
+// WebSocket is the interface of a WebSocket opened by a page.
+type WebSocket interface {
+	IsClosed() bool
+	On(event string, handler func(any) error) error
+	URL() string
+}
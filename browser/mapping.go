@@ -104,11 +104,19 @@ func mapRequest(vu moduleVU, r api.Request) mapping {
 		"postDataBuffer":      r.PostDataBuffer,
 		"postDataJSON":        r.PostDataJSON,
 		"redirectedFrom": func() *goja.Object {
-			mr := mapRequest(vu, r.RedirectedFrom())
+			from := r.RedirectedFrom()
+			if from == nil {
+				return nil
+			}
+			mr := mapRequest(vu, from)
 			return rt.ToValue(mr).ToObject(rt)
 		},
 		"redirectedTo": func() *goja.Object {
-			mr := mapRequest(vu, r.RedirectedTo())
+			to := r.RedirectedTo()
+			if to == nil {
+				return nil
+			}
+			mr := mapRequest(vu, to)
 			return rt.ToValue(mr).ToObject(rt)
 		},
 		"resourceType": r.ResourceType,
@@ -369,9 +377,13 @@ func mapFrame(vu moduleVU, f api.Frame) mapping {
 			mf := mapFrame(vu, f.ParentFrame())
 			return rt.ToValue(mf).ToObject(rt)
 		},
-		"press":         f.Press,
-		"selectOption":  f.SelectOption,
-		"setContent":    f.SetContent,
+		"press":        f.Press,
+		"selectOption": f.SelectOption,
+		"setContent": func(html string, opts goja.Value) *goja.Promise {
+			return k6ext.Promise(vu.Context(), func() (any, error) {
+				return nil, f.SetContent(html, opts) //nolint:wrapcheck
+			})
+		},
 		"setInputFiles": f.SetInputFiles,
 		"tap":           f.Tap,
 		"textContent":   f.TextContent,
@@ -444,15 +456,16 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 				return nil, err //nolint:wrapcheck
 			})
 		},
-		"close":                   p.Close,
-		"content":                 p.Content,
-		"context":                 p.Context,
-		"dblclick":                p.Dblclick,
-		"dispatchEvent":           p.DispatchEvent,
-		"dragAndDrop":             p.DragAndDrop,
-		"emulateMedia":            p.EmulateMedia,
-		"emulateVisionDeficiency": p.EmulateVisionDeficiency,
-		"evaluate":                p.Evaluate,
+		"close":                    p.Close,
+		"content":                  p.Content,
+		"context":                  p.Context,
+		"dblclick":                 p.Dblclick,
+		"dispatchEvent":            p.DispatchEvent,
+		"dragAndDrop":              p.DragAndDrop,
+		"emulateMedia":             p.EmulateMedia,
+		"emulateNetworkConditions": p.EmulateNetworkConditions,
+		"emulateVisionDeficiency":  p.EmulateVisionDeficiency,
+		"evaluate":                 p.Evaluate,
 		"evaluateHandle": func(pageFunc goja.Value, args ...goja.Value) (mapping, error) {
 			jsh, err := p.EvaluateHandle(pageFunc, args...)
 			if err != nil {
@@ -504,6 +517,7 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 		"isEnabled":  p.IsEnabled,
 		"isHidden":   p.IsHidden,
 		"isVisible":  p.IsVisible,
+		"indexedDB":  rt.ToValue(p.GetIndexedDB()).ToObject(rt),
 		"keyboard":   rt.ToValue(p.GetKeyboard()).ToObject(rt),
 		"locator": func(selector string, opts goja.Value) *goja.Object {
 			ml := mapLocator(vu, p.Locator(selector, opts))
@@ -513,19 +527,42 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 			mf := mapFrame(vu, p.MainFrame())
 			return rt.ToValue(mf).ToObject(rt)
 		},
-		"mouse":  rt.ToValue(p.GetMouse()).ToObject(rt),
+		"metrics": p.Metrics,
+		"mouse":   rt.ToValue(p.GetMouse()).ToObject(rt),
+		"on": func(event string, handler goja.Callable) error {
+			return p.On(event, func(data any) error {
+				if ws, ok := data.(api.WebSocket); ok {
+					data = mapWebSocket(vu, ws)
+				}
+				_, err := handler(goja.Undefined(), rt.ToValue(data))
+				return err //nolint:wrapcheck
+			})
+		},
 		"opener": p.Opener,
 		"pause":  p.Pause,
 		"pdf":    p.Pdf,
 		"press":  p.Press,
-		"reload": func(opts goja.Value) *goja.Object {
-			r := mapResponse(vu, p.Reload(opts))
-			return rt.ToValue(r).ToObject(rt)
-		},
-		"route":                       p.Route,
-		"screenshot":                  p.Screenshot,
-		"selectOption":                p.SelectOption,
-		"setContent":                  p.SetContent,
+		"reload": func(opts goja.Value) *goja.Promise {
+			return k6ext.Promise(vu.Context(), func() (any, error) {
+				resp, err := p.Reload(opts)
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+
+				return mapResponse(vu, resp), nil
+			})
+		},
+		"route": func(url, handler goja.Value) {
+			p.Route(url, mapRouteHandler(vu, handler))
+		},
+		"routeFromHAR": p.RouteFromHAR,
+		"screenshot":   p.Screenshot,
+		"selectOption": p.SelectOption,
+		"setContent": func(html string, opts goja.Value) *goja.Promise {
+			return k6ext.Promise(vu.Context(), func() (any, error) {
+				return nil, p.SetContent(html, opts) //nolint:wrapcheck
+			})
+		},
 		"setDefaultNavigationTimeout": p.SetDefaultNavigationTimeout,
 		"setDefaultTimeout":           p.SetDefaultTimeout,
 		"setExtraHTTPHeaders":         p.SetExtraHTTPHeaders,
@@ -533,6 +570,7 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 		"setViewportSize":             p.SetViewportSize,
 		"tap":                         p.Tap,
 		"textContent":                 p.TextContent,
+		"throttleCPU":                 p.ThrottleCPU,
 		"title":                       p.Title,
 		"touchscreen":                 rt.ToValue(p.GetTouchscreen()).ToObject(rt),
 		"type":                        p.Type,
@@ -557,8 +595,34 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 				return mapResponse(vu, resp), nil
 			})
 		},
-		"waitForRequest":  p.WaitForRequest,
-		"waitForResponse": p.WaitForResponse,
+		"waitForRequest": func(urlOrPredicate, opts goja.Value) *goja.Promise {
+			// start waiting before returning the promise to not miss the requests.
+			wait, err := p.WaitForRequest(mapRequestMatcher(vu, urlOrPredicate), opts)
+			return k6ext.Promise(vu.Context(), func() (result any, reason error) {
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+				req, err := wait()
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+				return mapRequest(vu, req), nil
+			})
+		},
+		"waitForResponse": func(urlOrPredicate, opts goja.Value) *goja.Promise {
+			// start waiting before returning the promise to not miss the responses.
+			wait, err := p.WaitForResponse(mapResponseMatcher(vu, urlOrPredicate), opts)
+			return k6ext.Promise(vu.Context(), func() (result any, reason error) {
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+				resp, err := wait()
+				if err != nil {
+					return nil, err //nolint:wrapcheck
+				}
+				return mapResponse(vu, resp), nil
+			})
+		},
 		"waitForSelector": func(selector string, opts goja.Value) (mapping, error) {
 			eh, err := p.WaitForSelector(selector, opts)
 			if err != nil {
@@ -600,6 +664,88 @@ func mapPage(vu moduleVU, p api.Page) mapping {
 	return maps
 }
 
+// mapWebSocket to the JS module.
+func mapWebSocket(vu moduleVU, ws api.WebSocket) mapping {
+	rt := vu.Runtime()
+	return mapping{
+		"isClosed": ws.IsClosed,
+		"on": func(event string, handler goja.Callable) error {
+			return ws.On(event, func(data any) error {
+				if ws, ok := data.(api.WebSocket); ok {
+					data = mapWebSocket(vu, ws)
+				}
+				_, err := handler(goja.Undefined(), rt.ToValue(data))
+				return err //nolint:wrapcheck
+			})
+		},
+		"url": ws.URL,
+	}
+}
+
+// mapRoute to the JS module.
+func mapRoute(vu moduleVU, r api.Route) mapping {
+	rt := vu.Runtime()
+	return mapping{
+		"abort":    r.Abort,
+		"continue": r.Continue,
+		"fulfill":  r.Fulfill,
+		"request": func() *goja.Object {
+			mr := mapRequest(vu, r.Request())
+			return rt.ToValue(mr).ToObject(rt)
+		},
+	}
+}
+
+// mapRouteHandler returns a route handler that
+// calls the JS handler with the mapped route.
+func mapRouteHandler(vu moduleVU, handler goja.Value) api.RouteHandler {
+	rh := api.RouteHandler{Value: handler}
+	if fn, ok := goja.AssertFunction(handler); ok {
+		rh.Call = func(r api.Route) error {
+			_, err := fn(goja.Undefined(), vu.Runtime().ToValue(mapRoute(vu, r)))
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return rh
+}
+
+// mapRequestMatcher maps the URL or the predicate of Page.waitForRequest.
+func mapRequestMatcher(vu moduleVU, urlOrPredicate goja.Value) api.RequestMatcher {
+	fn, ok := goja.AssertFunction(urlOrPredicate)
+	if !ok {
+		return api.RequestMatcher{URL: urlOrPredicate}
+	}
+
+	return api.RequestMatcher{
+		Predicate: func(r api.Request) (bool, error) {
+			v, err := fn(goja.Undefined(), vu.Runtime().ToValue(mapRequest(vu, r)))
+			if err != nil {
+				return false, err //nolint:wrapcheck
+			}
+			return v.ToBoolean(), nil
+		},
+	}
+}
+
+// mapResponseMatcher maps the URL or the predicate of Page.waitForResponse.
+func mapResponseMatcher(vu moduleVU, urlOrPredicate goja.Value) api.ResponseMatcher {
+	fn, ok := goja.AssertFunction(urlOrPredicate)
+	if !ok {
+		return api.ResponseMatcher{URL: urlOrPredicate}
+	}
+
+	return api.ResponseMatcher{
+		Predicate: func(r api.Response) (bool, error) {
+			v, err := fn(goja.Undefined(), vu.Runtime().ToValue(mapResponse(vu, r)))
+			if err != nil {
+				return false, err //nolint:wrapcheck
+			}
+			return v.ToBoolean(), nil
+		},
+	}
+}
+
 // mapWorker to the JS module.
 func mapWorker(vu moduleVU, w api.Worker) mapping {
 	return mapping{
@@ -623,20 +769,59 @@ func mapBrowserContext(vu moduleVU, bc api.BrowserContext) mapping {
 		"addCookies":       bc.AddCookies,
 		"addInitScript":    bc.AddInitScript,
 		"browser":          bc.Browser,
+		"clearCache":       bc.ClearCache,
 		"clearCookies":     bc.ClearCookies,
 		"clearPermissions": bc.ClearPermissions,
+		"clearStorage":     bc.ClearStorage,
 		"close":            bc.Close,
-		"cookies": func() ([]any, error) {
-			cc, err := bc.Cookies()
+		"cookies": func(urls ...goja.Value) ([]*api.Cookie, error) {
+			// urls can be given either as separate arguments or as an array.
+			var us []string
+			for _, u := range urls {
+				if uu, ok := u.Export().([]any); ok {
+					for _, u := range uu {
+						us = append(us, fmt.Sprint(u))
+					}
+					continue
+				}
+				us = append(us, u.String())
+			}
+			cc, err := bc.Cookies(us...)
 			ctx := vu.Context()
 			panicIfFatalError(ctx, err)
 			return cc, err //nolint:wrapcheck
 		},
-		"exposeBinding":               bc.ExposeBinding,
-		"exposeFunction":              bc.ExposeFunction,
-		"grantPermissions":            bc.GrantPermissions,
-		"newCDPSession":               bc.NewCDPSession,
-		"route":                       bc.Route,
+		"exposeBinding":  bc.ExposeBinding,
+		"exposeFunction": bc.ExposeFunction,
+		"extensionWorkers": func() *goja.Object {
+			var mws []mapping
+			for _, w := range bc.ExtensionWorkers() {
+				mws = append(mws, mapWorker(vu, w))
+			}
+			return rt.ToValue(mws).ToObject(rt)
+		},
+		"grantPermissions": bc.GrantPermissions,
+		"newCDPSession":    bc.NewCDPSession,
+		"on": func(event string, handler goja.Callable) error {
+			return bc.On(event, func(data any) error {
+				if w, ok := data.(api.Worker); ok {
+					data = mapWorker(vu, w)
+				}
+				_, err := handler(goja.Undefined(), rt.ToValue(data))
+				return err //nolint:wrapcheck
+			})
+		},
+		"route": func(url, handler goja.Value) {
+			bc.Route(url, mapRouteHandler(vu, handler))
+		},
+		"routeFromHAR": bc.RouteFromHAR,
+		"serviceWorkers": func() *goja.Object {
+			var mws []mapping
+			for _, w := range bc.ServiceWorkers() {
+				mws = append(mws, mapWorker(vu, w))
+			}
+			return rt.ToValue(mws).ToObject(rt)
+		},
 		"setDefaultNavigationTimeout": bc.SetDefaultNavigationTimeout,
 		"setDefaultTimeout":           bc.SetDefaultTimeout,
 		"setExtraHTTPHeaders": func(headers map[string]string) *goja.Promise {
@@ -719,9 +904,20 @@ func mapBrowserType(vu moduleVU, bt api.BrowserType, wsURL string, isRemoteBrows
 			m := mapBrowser(vu, b)
 			return rt.ToValue(m).ToObject(rt)
 		},
-		"executablePath":          bt.ExecutablePath,
-		"launchPersistentContext": bt.LaunchPersistentContext,
-		"name":                    bt.Name,
+		"executablePath": bt.ExecutablePath,
+		"launchPersistentContext": func(userDataDir string, opts goja.Value) *goja.Object {
+			// A remote browser has its own user data directory.
+			if isRemoteBrowser {
+				k6common.Throw(rt, errors.New("launchPersistentContext can't be used with a remote browser"))
+			}
+
+			bctx, pid := bt.LaunchPersistentContext(userDataDir, opts)
+			// store the pid so we can kill it later on panic.
+			vu.registerPid(pid)
+			m := mapBrowserContext(vu, bctx)
+			return rt.ToValue(m).ToObject(rt)
+		},
+		"name": bt.Name,
 		"launch": func(opts goja.Value) *goja.Object {
 			// If browser is remote, transition from launch
 			// to connect and avoid storing the browser pid
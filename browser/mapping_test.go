@@ -32,6 +32,7 @@ func customMappings() map[string]string {
 		"ElementHandle.query":    "$",
 		"ElementHandle.queryAll": "$$",
 		// getters
+		"Page.getIndexedDB":   "indexedDB",
 		"Page.getKeyboard":    "keyboard",
 		"Page.getMouse":       "mouse",
 		"Page.getTouchscreen": "touchscreen",
@@ -139,6 +140,7 @@ func TestMappings(t *testing.T) {
 			apiInterface: (*api.Page)(nil),
 			mapp: func() mapping {
 				return mapPage(moduleVU{VU: vu}, &common.Page{
+					IndexedDB:   &common.IndexedDB{},
 					Keyboard:    &common.Keyboard{},
 					Mouse:       &common.Mouse{},
 					Touchscreen: &common.Touchscreen{},
@@ -175,6 +177,18 @@ func TestMappings(t *testing.T) {
 				return mapResponse(moduleVU{VU: vu}, &common.Response{})
 			},
 		},
+		"mapRoute": {
+			apiInterface: (*api.Route)(nil),
+			mapp: func() mapping {
+				return mapRoute(moduleVU{VU: vu}, &common.Route{})
+			},
+		},
+		"mapWebSocket": {
+			apiInterface: (*api.WebSocket)(nil),
+			mapp: func() mapping {
+				return mapWebSocket(moduleVU{VU: vu}, &common.WebSocket{})
+			},
+		},
 		"mapWorker": {
 			apiInterface: (*api.Worker)(nil),
 			mapp: func() mapping {
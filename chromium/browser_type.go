@@ -2,8 +2,8 @@ package chromium
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"math/rand"
 	"os"
@@ -20,7 +20,6 @@ import (
 	"github.com/grafana/xk6-browser/log"
 	"github.com/grafana/xk6-browser/storage"
 
-	k6common "go.k6.io/k6/js/common"
 	k6modules "go.k6.io/k6/js/modules"
 	k6lib "go.k6.io/k6/lib"
 
@@ -164,7 +163,7 @@ func (b *BrowserType) Launch() (_ api.Browser, browserProcessID int) {
 		k6ext.Panic(ctx, "initializing browser type: %w", err)
 	}
 
-	bp, pid, err := b.launch(ctx, browserOpts, logger)
+	bp, pid, err := b.launch(ctx, browserOpts, logger, "")
 	if err != nil {
 		err = &k6ext.UserFriendlyError{
 			Err:     err,
@@ -176,19 +175,43 @@ func (b *BrowserType) Launch() (_ api.Browser, browserProcessID int) {
 	return bp, pid
 }
 
+// launch launches the browser with the user data directory. If the user
+// data directory is empty, it uses a temporary one that is removed when
+// the browser exits.
 func (b *BrowserType) launch(
-	ctx context.Context, opts *common.BrowserOptions, logger *log.Logger,
+	ctx context.Context, opts *common.BrowserOptions, logger *log.Logger, userDataDir string,
 ) (_ *common.Browser, pid int, _ error) {
 	flags, err := prepareFlags(opts, &(b.vu.State()).Options)
 	if err != nil {
 		return nil, 0, fmt.Errorf("%w", err)
 	}
+	if userDataDir != "" {
+		flags["user-data-dir"] = userDataDir
+	}
 	dataDir := &storage.Dir{}
 	if err := dataDir.Make("", flags["user-data-dir"]); err != nil {
 		return nil, 0, fmt.Errorf("%w", err)
 	}
 	flags["user-data-dir"] = dataDir.Dir
 
+	if opts.EmbeddedProxy {
+		proxy := newLocalProxy(b.vu.State().Dialer, logger)
+		proxyURL, err := proxy.start()
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w", err)
+		}
+		flags["proxy-server"] = proxyURL
+		// Chromium bypasses the proxy for the loopback addresses by default.
+		flags["proxy-bypass-list"] = "<-loopback>"
+
+		go func(c context.Context) {
+			<-c.Done()
+			if err := proxy.close(); err != nil {
+				logger.Errorf("BrowserType:Launch", "closing the local proxy: %v", err)
+			}
+		}(ctx)
+	}
+
 	go func(c context.Context) {
 		defer func() {
 			if err := dataDir.Cleanup(); err != nil {
@@ -221,11 +244,37 @@ func (b *BrowserType) launch(
 	return browser, browserProc.Pid(), nil
 }
 
-// LaunchPersistentContext launches the browser with persistent storage.
-func (b *BrowserType) LaunchPersistentContext(userDataDir string, opts goja.Value) api.Browser {
-	rt := b.vu.Runtime()
-	k6common.Throw(rt, errors.New("BrowserType.LaunchPersistentContext(userDataDir, opts) has not been implemented yet"))
-	return nil
+// LaunchPersistentContext launches the browser with the user data directory
+// and returns its default browser context, configured with the options.
+// Unlike Launch, the user data directory is kept when the browser exits, so
+// the profile data, such as caches, IndexedDB and service workers, is reused
+// between runs.
+func (b *BrowserType) LaunchPersistentContext(
+	userDataDir string, opts goja.Value,
+) (_ api.BrowserContext, browserProcessID int) {
+	ctx, browserOpts, logger, err := b.init(false)
+	if err != nil {
+		k6ext.Panic(ctx, "initializing browser type: %w", err)
+	}
+	if userDataDir == "" {
+		k6ext.Panic(ctx, "launching persistent context: userDataDir must be set")
+	}
+
+	bp, pid, err := b.launch(ctx, browserOpts, logger, userDataDir)
+	if err != nil {
+		err = &k6ext.UserFriendlyError{
+			Err:     err,
+			Timeout: browserOpts.Timeout,
+		}
+		k6ext.Panic(ctx, "%w", err)
+	}
+	bctx, err := bp.NewPersistentContext(opts)
+	if err != nil {
+		bp.Close()
+		k6ext.Panic(ctx, "launching persistent context: %w", err)
+	}
+
+	return bctx, pid
 }
 
 // Name returns the name of this browser type.
@@ -369,9 +418,10 @@ func prepareFlags(lopts *common.BrowserOptions, k6opts *k6lib.Options) (map[stri
 		f["blink-settings"] = "primaryHoverType=2,availableHoverTypes=2,primaryPointerType=4,availablePointerTypes=4"
 	}
 	ignoreDefaultArgsFlags(f, lopts.IgnoreDefaultArgs)
+	setExtensionsFlags(f, lopts)
 
 	setFlagsFromArgs(f, lopts.Args)
-	if err := setFlagsFromK6Options(f, k6opts); err != nil {
+	if err := setFlagsFromK6Options(f, k6opts, lopts.EmbeddedProxy); err != nil {
 		return nil, err
 	}
 
@@ -385,6 +435,21 @@ func ignoreDefaultArgsFlags(flags map[string]any, toIgnore []string) {
 	}
 }
 
+// setExtensionsFlags loads the unpacked extensions, and disables any other
+// extension. Extensions only work in the new headless mode.
+func setExtensionsFlags(flags map[string]any, lopts *common.BrowserOptions) {
+	if len(lopts.Extensions) == 0 {
+		return
+	}
+	dirs := strings.Join(lopts.Extensions, ",")
+	delete(flags, "disable-extensions")
+	flags["load-extension"] = dirs
+	flags["disable-extensions-except"] = dirs
+	if lopts.Headless {
+		flags["headless"] = "new"
+	}
+}
+
 // setFlagsFromArgs fills flags by parsing the args slice.
 // This is used for passing the "arg=value" arguments along with other launch options
 // when launching a new Chrome browser.
@@ -402,10 +467,17 @@ func setFlagsFromArgs(flags map[string]any, args []string) {
 
 // setFlagsFromK6Options adds additional data to flags considering the k6 options.
 // Such as: "host-resolver-rules" for blocking requests.
-func setFlagsFromK6Options(flags map[string]any, k6opts *k6lib.Options) error {
+func setFlagsFromK6Options(flags map[string]any, k6opts *k6lib.Options, embeddedProxy bool) error {
 	if k6opts == nil {
 		return nil
 	}
+	// The embedded proxy resolves the hosts with the k6 dialer, but it
+	// tunnels HTTPS, so the TLS options still have to be passed to the
+	// browser.
+	if embeddedProxy {
+		setTLSFlags(flags, k6opts)
+		return nil
+	}
 
 	hostResolver := []string{}
 	if currHostResolver, ok := flags["host-resolver-rules"]; ok {
@@ -442,6 +514,33 @@ func setFlagsFromK6Options(flags map[string]any, k6opts *k6lib.Options) error {
 	return nil
 }
 
+// setTLSFlags sets the flags for the k6 TLS options that Chromium supports.
+// The flags that are already set by the browser arguments are kept.
+func setTLSFlags(flags map[string]any, k6opts *k6lib.Options) {
+	setFlag := func(name string, value any) {
+		if _, ok := flags[name]; !ok {
+			flags[name] = value
+		}
+	}
+	if k6opts.InsecureSkipTLSVerify.Bool {
+		setFlag("ignore-certificate-errors", true)
+	}
+	if k6opts.TLSVersion == nil {
+		return
+	}
+	// Chromium only supports TLS 1.2 and 1.3.
+	versions := map[k6lib.TLSVersion]string{
+		tls.VersionTLS12: "tls1.2",
+		tls.VersionTLS13: "tls1.3",
+	}
+	if v, ok := versions[k6opts.TLSVersion.Min]; ok {
+		setFlag("ssl-version-min", v)
+	}
+	if v, ok := versions[k6opts.TLSVersion.Max]; ok {
+		setFlag("ssl-version-max", v)
+	}
+}
+
 // makeLogger makes and returns an extension wide logger.
 func makeLogger(ctx context.Context) (*log.Logger, error) {
 	var (
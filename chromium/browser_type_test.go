@@ -1,6 +1,7 @@
 package chromium
 
 import (
+	"crypto/tls"
 	"net"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
 )
 
 func TestBrowserTypePrepareFlags(t *testing.T) {
@@ -103,6 +105,34 @@ func TestBrowserTypePrepareFlags(t *testing.T) {
 			changeK6Opts:  &k6lib.Options{},
 			expChangedVal: nil,
 		},
+		{
+			flag:       "host-resolver-rules",
+			expInitVal: nil,
+			changeOpts: &common.BrowserOptions{EmbeddedProxy: true},
+			changeK6Opts: &k6lib.Options{
+				Hosts: types.NullHosts{Trie: hosts, Valid: true},
+			},
+			expChangedVal: nil,
+		},
+		{
+			flag:       "ignore-certificate-errors",
+			expInitVal: nil,
+			changeOpts: &common.BrowserOptions{EmbeddedProxy: true},
+			changeK6Opts: &k6lib.Options{
+				InsecureSkipTLSVerify: null.BoolFrom(true),
+				TLSVersion: &k6lib.TLSVersions{
+					Min: tls.VersionTLS12,
+					Max: tls.VersionTLS13,
+				},
+			},
+			expChangedVal: true,
+			post: func(t *testing.T, flags map[string]any) {
+				t.Helper()
+
+				assert.Equal(t, "tls1.2", flags["ssl-version-min"])
+				assert.Equal(t, "tls1.3", flags["ssl-version-max"])
+			},
+		},
 		{
 			flag:          "headless",
 			expInitVal:    false,
@@ -117,6 +147,24 @@ func TestBrowserTypePrepareFlags(t *testing.T) {
 				}
 			},
 		},
+		{
+			flag:          "load-extension",
+			expInitVal:    nil,
+			changeOpts:    &common.BrowserOptions{Extensions: []string{"/ext/a", "/ext/b"}},
+			expChangedVal: "/ext/a,/ext/b",
+			post: func(t *testing.T, flags map[string]any) {
+				t.Helper()
+
+				assert.Equal(t, "/ext/a,/ext/b", flags["disable-extensions-except"])
+				assert.NotContains(t, flags, "disable-extensions")
+			},
+		},
+		{
+			flag:          "headless",
+			expInitVal:    false,
+			changeOpts:    &common.BrowserOptions{Headless: true, Extensions: []string{"/ext/a"}},
+			expChangedVal: "new",
+		},
 	}
 
 	for _, tc := range testCases {
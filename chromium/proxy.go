@@ -0,0 +1,208 @@
+package chromium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/grafana/xk6-browser/log"
+
+	k6lib "go.k6.io/k6/lib"
+	k6netext "go.k6.io/k6/lib/netext"
+)
+
+// hopByHopHeaders are the headers that are meaningful only for
+// a single connection and must not be forwarded by a proxy.
+var hopByHopHeaders = []string{ //nolint:gochecknoglobals
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// localProxy is an HTTP proxy that runs in the k6 process. The browser
+// is launched with it as its proxy server, so that the browser connects
+// to the hosts through the k6 dialer of the VU. This way, the k6 DNS,
+// hosts, blockHostnames and blacklistIPs options apply to the browser
+// traffic the same as they do to the k6 http module.
+//
+// HTTPS and WebSocket traffic is tunneled with CONNECT, so the TLS
+// handshake is still done by the browser itself. The k6 TLS options
+// are passed to the browser as flags instead (see setTLSFlags).
+type localProxy struct {
+	dialer    k6lib.DialContexter
+	transport *http.Transport
+	server    *http.Server
+	listener  net.Listener
+	logger    *log.Logger
+}
+
+// newLocalProxy returns a new local proxy that dials with the given dialer.
+func newLocalProxy(dialer k6lib.DialContexter, logger *log.Logger) *localProxy {
+	p := &localProxy{
+		dialer: dialer,
+		transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+		logger: logger,
+	}
+	p.server = &http.Server{Handler: p} //nolint:gosec
+
+	return p
+}
+
+// start starts listening on a random loopback port,
+// and returns the URL of the proxy.
+func (p *localProxy) start() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("starting local proxy: %w", err)
+	}
+	p.listener = l
+
+	go func() {
+		if err := p.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Errorf("localProxy:start", "serving: %v", err)
+		}
+	}()
+
+	return "http://" + l.Addr().String(), nil
+}
+
+// close stops the proxy and closes its idle upstream connections.
+func (p *localProxy) close() error {
+	p.transport.CloseIdleConnections()
+	if err := p.server.Close(); err != nil {
+		return fmt.Errorf("closing local proxy: %w", err)
+	}
+
+	return nil
+}
+
+func (p *localProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	p.forward(w, r)
+}
+
+// tunnel connects to the requested host and copies
+// the data between the browser and the host.
+func (p *localProxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	p.logger.Debugf("localProxy:tunnel", "host:%s", r.Host)
+
+	upstream, err := p.dialer.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		p.fail(w, r, err)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		_ = upstream.Close()
+		http.Error(w, "hijacking is not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+		p.logger.Errorf("localProxy:tunnel", "hijacking connection to %s: %v", r.Host, err)
+		return
+	}
+	if _, err := conn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		_ = upstream.Close()
+		_ = conn.Close()
+		return
+	}
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+	)
+	closeAll := func() {
+		_ = upstream.Close()
+		_ = conn.Close()
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		// the buffered reader may already hold the data sent after CONNECT.
+		_, _ = io.Copy(upstream, buf)
+		once.Do(closeAll)
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(conn, upstream)
+		once.Do(closeAll)
+	}()
+	wg.Wait()
+}
+
+// forward sends a plain HTTP request to the host and copies the response back.
+func (p *localProxy) forward(w http.ResponseWriter, r *http.Request) {
+	p.logger.Debugf("localProxy:forward", "method:%s url:%s", r.Method, r.URL)
+
+	if !r.URL.IsAbs() {
+		http.Error(w, "the proxy only accepts absolute URLs", http.StatusBadRequest)
+		return
+	}
+
+	req := r.Clone(r.Context())
+	req.RequestURI = ""
+	removeHopByHopHeaders(req.Header)
+
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		p.fail(w, r, err)
+		return
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	removeHopByHopHeaders(resp.Header)
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil && !errors.Is(err, context.Canceled) {
+		p.logger.Debugf("localProxy:forward", "copying response body of %s: %v", r.URL, err)
+	}
+}
+
+// fail responds with an error status for a request that couldn't
+// reach the host. Hosts blocked by the k6 options are forbidden.
+func (p *localProxy) fail(w http.ResponseWriter, r *http.Request, err error) {
+	p.logger.Debugf("localProxy:fail", "host:%s err:%v", r.Host, err)
+
+	var (
+		blockedHost k6netext.BlockedHostError
+		blockedIP   k6netext.BlackListedIPError
+		status      = http.StatusBadGateway
+	)
+	if errors.As(err, &blockedHost) || errors.As(err, &blockedIP) {
+		status = http.StatusForbidden
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func removeHopByHopHeaders(h http.Header) {
+	for _, f := range h.Values("Connection") {
+		for _, k := range strings.Split(f, ",") {
+			h.Del(strings.TrimSpace(k))
+		}
+	}
+	for _, k := range hopByHopHeaders {
+		h.Del(k)
+	}
+}
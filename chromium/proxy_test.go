@@ -0,0 +1,82 @@
+package chromium
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/log"
+
+	k6netext "go.k6.io/k6/lib/netext"
+	k6types "go.k6.io/k6/lib/types"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalProxy(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello from "+r.URL.Path)
+	})
+	httpSrv := httptest.NewServer(handler)
+	defer httpSrv.Close()
+	httpsSrv := httptest.NewTLSServer(handler)
+	defer httpsSrv.Close()
+
+	blocked, err := k6types.NewHostnameTrie([]string{"*.blocked.test"})
+	require.NoError(t, err)
+	dialer := k6netext.NewDialer(net.Dialer{Timeout: time.Second},
+		k6netext.NewResolver(net.LookupIP, 0, k6types.DNSfirst, k6types.DNSpreferIPv4))
+	dialer.BlockedHostnames = blocked
+
+	proxy := newLocalProxy(dialer, log.NewNullLogger())
+	proxyURL, err := proxy.start()
+	require.NoError(t, err)
+	defer proxy.close() //nolint:errcheck
+
+	pu, err := url.Parse(proxyURL)
+	require.NoError(t, err)
+	client := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyURL(pu),
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+	}
+
+	get := func(t *testing.T, u string) (int, string) {
+		t.Helper()
+
+		resp, err := client.Get(u) //nolint:noctx
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("http", func(t *testing.T) {
+		status, body := get(t, httpSrv.URL+"/plain")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello from /plain", body)
+	})
+	t.Run("https", func(t *testing.T) {
+		status, body := get(t, httpsSrv.URL+"/tunnel")
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "hello from /tunnel", body)
+	})
+	t.Run("blocked", func(t *testing.T) {
+		status, _ := get(t, "http://www.blocked.test/")
+		assert.Equal(t, http.StatusForbidden, status)
+	})
+	assert.Positive(t, atomic.LoadInt64(&dialer.BytesRead), "should read through the k6 dialer")
+	assert.Positive(t, atomic.LoadInt64(&dialer.BytesWritten), "should write through the k6 dialer")
+}
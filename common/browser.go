@@ -85,6 +85,9 @@ func NewBrowser(
 	if err := b.connect(); err != nil {
 		return nil, err
 	}
+	if !browserOpts.isRemoteBrowser && browserProc.Pid() > 0 {
+		go b.sampleProcessMetrics(processMetricsInterval)
+	}
 	return b, nil
 }
 
@@ -230,6 +233,11 @@ func (b *Browser) onAttachedToTarget(ev *target.EventAttachedToTarget) {
 		browserCtx = b.getDefaultBrowserContextOrByID(targetPage.BrowserContextID)
 	)
 
+	// Background pages of the extensions are exposed as workers.
+	if targetPage.Type == "service_worker" || targetPage.Type == "background_page" {
+		b.attachWorker(ev, browserCtx)
+		return
+	}
 	if !b.isAttachedPageValid(ev, browserCtx) {
 		return // Ignore this page.
 	}
@@ -268,6 +276,37 @@ func (b *Browser) onAttachedToTarget(ev *target.EventAttachedToTarget) {
 	}
 }
 
+// attachWorker registers the service worker or the background
+// page of an extension to its browser context as a worker.
+func (b *Browser) attachWorker(ev *target.EventAttachedToTarget, browserCtx *BrowserContext) {
+	ti := ev.TargetInfo
+
+	session := b.conn.getSession(ev.SessionID)
+	if session == nil || browserCtx == nil {
+		b.logger.Debugf("Browser:attachWorker",
+			"session closed or missing browser context. sid:%v tid:%v bctxid:%v",
+			ev.SessionID, ti.TargetID, ti.BrowserContextID)
+		return
+	}
+	w, err := NewWorker(b.ctx, session, ti.TargetID, ti.URL, b.logger)
+	if err != nil && b.isPageAttachmentErrorIgnorable(ev, session, err) {
+		return
+	}
+	if err != nil {
+		k6ext.Panic(b.ctx, "creating a new %s: %w", ti.Type, err)
+	}
+
+	b.sessionIDtoTargetIDMu.Lock()
+	b.sessionIDtoTargetID[ev.SessionID] = ti.TargetID
+	b.sessionIDtoTargetIDMu.Unlock()
+
+	if ti.Type == "background_page" {
+		browserCtx.addBackgroundWorker(ti.TargetID, w)
+		return
+	}
+	browserCtx.addServiceWorker(ti.TargetID, w)
+}
+
 // attachNewPage registers the page as an active page and attaches the sessionID with the targetID.
 func (b *Browser) attachNewPage(p *Page, ev *target.EventAttachedToTarget) {
 	targetPage := ev.TargetInfo
@@ -373,6 +412,23 @@ func (b *Browser) onDetachedFromTarget(ev *target.EventDetachedFromTarget) {
 
 		delete(b.pages, targetID)
 		t.didClose()
+		return
+	}
+	b.closeWorker(targetID)
+}
+
+// closeWorker unregisters the worker from its browser context.
+func (b *Browser) closeWorker(id target.ID) {
+	b.contextsMu.RLock()
+	defer b.contextsMu.RUnlock()
+
+	if b.defaultContext != nil && b.defaultContext.closeWorker(id) {
+		return
+	}
+	for _, bctx := range b.contexts {
+		if bctx.closeWorker(id) {
+			return
+		}
 	}
 }
 
@@ -513,25 +569,62 @@ func (b *Browser) IsConnected() bool {
 
 // NewContext creates a new incognito-like browser context.
 func (b *Browser) NewContext(opts goja.Value) (api.BrowserContext, error) {
+	browserCtxOpts := NewBrowserContextOptions()
+	if err := browserCtxOpts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing newContext options: %w", err)
+	}
+
 	action := target.CreateBrowserContext().WithDisposeOnDetach(true)
+	if proxy := browserCtxOpts.Proxy; proxy != nil {
+		action = action.WithProxyServer(proxy.Server)
+		if proxy.Bypass != "" {
+			action = action.WithProxyBypassList(proxy.Bypass)
+		}
+	}
 	browserContextID, err := action.Do(cdp.WithExecutor(b.ctx, b.conn))
 	b.logger.Debugf("Browser:NewContext", "bctxid:%v", browserContextID)
 	if err != nil {
 		k6ext.Panic(b.ctx, "creating browser context ID %s: %w", browserContextID, err)
 	}
 
+	b.contextsMu.Lock()
+	defer b.contextsMu.Unlock()
+	browserCtx, err := NewBrowserContext(b.ctx, b, browserContextID, browserCtxOpts, b.logger)
+	if err != nil {
+		return nil, fmt.Errorf("new context: %w", err)
+	}
+	b.contexts[browserContextID] = browserCtx
+
+	return browserCtx, nil
+}
+
+// NewPersistentContext replaces the default browser context with a new one
+// configured with the options. The pages of the persistent context use the
+// user data directory of the browser, so its data is kept between runs.
+func (b *Browser) NewPersistentContext(opts goja.Value) (*BrowserContext, error) {
 	browserCtxOpts := NewBrowserContextOptions()
 	if err := browserCtxOpts.Parse(b.ctx, opts); err != nil {
-		k6ext.Panic(b.ctx, "parsing newContext options: %w", err)
+		return nil, fmt.Errorf("parsing persistent context options: %w", err)
+	}
+	// The proxy can only be set when creating a new browser context.
+	if browserCtxOpts.Proxy != nil {
+		return nil, errors.New("proxy option is not supported for a persistent context")
 	}
+	b.logger.Debugf("Browser:NewPersistentContext", "")
 
 	b.contextsMu.Lock()
 	defer b.contextsMu.Unlock()
-	browserCtx, err := NewBrowserContext(b.ctx, b, browserContextID, browserCtxOpts, b.logger)
+	browserCtx, err := NewBrowserContext(b.ctx, b, "", browserCtxOpts, b.logger)
 	if err != nil {
-		return nil, fmt.Errorf("new context: %w", err)
+		return nil, fmt.Errorf("new persistent context: %w", err)
 	}
-	b.contexts[browserContextID] = browserCtx
+	browserCtx.persistent = true
+	// The workers that started with the browser, such as the ones
+	// of the extensions, belong to the persistent context.
+	if b.defaultContext != nil {
+		browserCtx.adoptWorkers(b.defaultContext)
+	}
+	b.defaultContext = browserCtx
 
 	return browserCtx, nil
 }
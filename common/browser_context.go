@@ -2,8 +2,12 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/grafana/xk6-browser/api"
@@ -17,6 +21,7 @@ import (
 	cdpbrowser "github.com/chromedp/cdproto/browser"
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
+	cdppage "github.com/chromedp/cdproto/page"
 	"github.com/chromedp/cdproto/storage"
 	"github.com/chromedp/cdproto/target"
 	"github.com/dop251/goja"
@@ -43,7 +48,36 @@ type BrowserContext struct {
 	logger          *log.Logger
 	vu              k6modules.VU
 
+	// persistent is true if this is the default browser context
+	// of a browser launched with a user data directory.
+	persistent bool
+
+	// done is closed when the browser context is closed.
+	done     chan struct{}
+	doneOnce sync.Once
+
 	evaluateOnNewDocumentSources []string
+
+	// localStorage is the local storage of the storage state
+	// that is not restored yet, and localStorageScripts are the
+	// scripts that restore it on the new documents of the pages.
+	localStorageMu      sync.Mutex
+	localStorage        []*api.StorageStateOrigin
+	localStorageScripts map[*Page]cdppage.ScriptIdentifier
+
+	routesMu sync.RWMutex
+	routes   []*routeHandler
+
+	har          *harRecorder
+	harRoutersMu sync.RWMutex
+	harRouters   []*harRouter
+	// harUpdates are the HAR files of the routers of this browser
+	// context and of its pages that are updated when it's closed.
+	harUpdates []*harRecorder
+
+	workersMu         sync.RWMutex
+	serviceWorkers    map[target.ID]*Worker
+	backgroundWorkers map[target.ID]*Worker // background pages of the extensions
 }
 
 // NewBrowserContext creates a new browser context.
@@ -51,19 +85,27 @@ func NewBrowserContext(
 	ctx context.Context, browser *Browser, id cdp.BrowserContextID, opts *BrowserContextOptions, logger *log.Logger,
 ) (*BrowserContext, error) {
 	b := BrowserContext{
-		BaseEventEmitter: NewBaseEventEmitter(ctx),
-		ctx:              ctx,
-		browser:          browser,
-		id:               id,
-		opts:             opts,
-		logger:           logger,
-		vu:               k6ext.GetVU(ctx),
-		timeoutSettings:  NewTimeoutSettings(nil),
+		BaseEventEmitter:  NewBaseEventEmitter(ctx),
+		ctx:               ctx,
+		browser:           browser,
+		id:                id,
+		opts:              opts,
+		logger:            logger,
+		vu:                k6ext.GetVU(ctx),
+		timeoutSettings:   NewTimeoutSettings(nil),
+		done:              make(chan struct{}),
+		serviceWorkers:    make(map[target.ID]*Worker),
+		backgroundWorkers: make(map[target.ID]*Worker),
+
+		localStorageScripts: make(map[*Page]cdppage.ScriptIdentifier),
 	}
 
 	if opts != nil && len(opts.Permissions) > 0 {
 		b.GrantPermissions(opts.Permissions, nil)
 	}
+	if opts != nil && opts.RecordHAR != nil {
+		b.har = newHARRecorder(opts.RecordHAR)
+	}
 
 	rt := b.vu.Runtime()
 	wv := rt.ToValue(js.WebVitalIIFEScript)
@@ -75,6 +117,21 @@ func NewBrowserContext(
 	if err := b.AddInitScript(wvi, nil); err != nil {
 		return nil, fmt.Errorf("adding web vital init script to new browser context: %w", err)
 	}
+	if opts != nil && opts.ServiceWorkers == ServiceWorkersBlock {
+		if err := b.AddInitScript(rt.ToValue(js.BlockServiceWorkersScript), nil); err != nil {
+			return nil, fmt.Errorf("adding service workers script to new browser context: %w", err)
+		}
+	}
+	if opts != nil && opts.UserTimings {
+		if err := b.AddInitScript(rt.ToValue(js.UserTimingScript), nil); err != nil {
+			return nil, fmt.Errorf("adding user timing script to new browser context: %w", err)
+		}
+	}
+	if opts != nil && opts.StorageState != nil {
+		if err := b.restoreStorageState(opts.StorageState); err != nil {
+			return nil, fmt.Errorf("restoring storage state of new browser context: %w", err)
+		}
+	}
 
 	return &b, nil
 }
@@ -137,7 +194,10 @@ func (b *BrowserContext) applyAllInitScripts(p *Page) error {
 		}
 	}
 
-	return nil
+	b.localStorageMu.Lock()
+	defer b.localStorageMu.Unlock()
+
+	return b.addLocalStorageScript(p)
 }
 
 // Browser returns the browser instance that this browser context belongs to.
@@ -145,16 +205,62 @@ func (b *BrowserContext) Browser() api.Browser {
 	return b.browser
 }
 
-// ClearCookies clears cookies.
-func (b *BrowserContext) ClearCookies() {
+// ClearCache clears the HTTP cache of this browser context.
+// It returns an error if the browser context has no pages.
+func (b *BrowserContext) ClearCache() error {
+	b.logger.Debugf("BrowserContext:ClearCache", "bctxid:%v", b.id)
+
+	executor, err := b.storageExecutor()
+	if err != nil {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+	action := network.ClearBrowserCache()
+	if err := action.Do(cdp.WithExecutor(b.ctx, executor)); err != nil {
+		return fmt.Errorf("clearing cache: %w", err)
+	}
+
+	return nil
+}
+
+// ClearCookies clears the cookies of this browser context. If a name,
+// domain or path is given, only the cookies that match all of them are cleared.
+func (b *BrowserContext) ClearCookies(opts goja.Value) {
 	b.logger.Debugf("BrowserContext:ClearCookies", "bctxid:%v", b.id)
 
-	action := storage.ClearCookies().WithBrowserContextID(b.id)
-	if err := action.Do(b.ctx); err != nil {
+	copts := NewBrowserContextClearCookiesOptions()
+	if err := copts.Parse(b.ctx, opts); err != nil {
+		k6ext.Panic(b.ctx, "parsing clear cookies options: %w", err)
+	}
+	if err := b.clearCookies(copts); err != nil {
 		k6ext.Panic(b.ctx, "clearing cookies: %w", err)
 	}
 }
 
+func (b *BrowserContext) clearCookies(opts *BrowserContextClearCookiesOptions) error {
+	if !opts.selective() {
+		action := storage.ClearCookies().WithBrowserContextID(b.id)
+		if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+			return err //nolint:wrapcheck
+		}
+		return nil
+	}
+
+	cookies, err := b.cookies()
+	if err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		if !opts.matches(c) {
+			continue
+		}
+		if err := b.deleteCookie(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // ClearPermissions clears any permission overrides.
 func (b *BrowserContext) ClearPermissions() {
 	b.logger.Debugf("BrowserContext:ClearPermissions", "bctxid:%v", b.id)
@@ -165,21 +271,84 @@ func (b *BrowserContext) ClearPermissions() {
 	}
 }
 
+// ClearStorage clears the storage of the given origins, or of the origins
+// of the pages of this browser context if no origins are given. It returns
+// an error if the browser context has no pages or there are no origins to clear.
+func (b *BrowserContext) ClearStorage(opts goja.Value) error {
+	b.logger.Debugf("BrowserContext:ClearStorage", "bctxid:%v", b.id)
+
+	copts := NewBrowserContextClearStorageOptions()
+	if err := copts.Parse(b.ctx, opts); err != nil {
+		return fmt.Errorf("parsing clear storage options: %w", err)
+	}
+	executor, err := b.storageExecutor()
+	if err != nil {
+		return fmt.Errorf("clearing storage: %w", err)
+	}
+	origins := copts.Origins
+	if len(origins) == 0 {
+		origins = pagesOrigins(b.getPages())
+	}
+	if len(origins) == 0 {
+		return errors.New("clearing storage: no origins given and no pages with an origin")
+	}
+
+	types := strings.Join(copts.Types, ",")
+	for _, o := range origins {
+		origin, err := originOf(o)
+		if err != nil {
+			return fmt.Errorf("clearing storage: %w", err)
+		}
+		action := storage.ClearDataForOrigin(origin, types)
+		if err := action.Do(cdp.WithExecutor(b.ctx, executor)); err != nil {
+			return fmt.Errorf("clearing storage of origin %q: %w", origin, err)
+		}
+	}
+
+	return nil
+}
+
 // Close shuts down the browser context.
 func (b *BrowserContext) Close() {
 	b.logger.Debugf("BrowserContext:Close", "bctxid:%v", b.id)
 
-	if b.id == "" {
+	switch {
+	case b.persistent:
+		// Closing a persistent context closes its browser.
+		b.browser.Close()
+	case b.id == "":
 		k6ext.Panic(b.ctx, "default browser context can't be closed")
+	default:
+		if err := b.browser.disposeContext(b.id); err != nil {
+			k6ext.Panic(b.ctx, "disposing browser context: %w", err)
+		}
 	}
-	if err := b.browser.disposeContext(b.id); err != nil {
-		k6ext.Panic(b.ctx, "disposing browser context: %w", err)
+	b.doneOnce.Do(func() { close(b.done) })
+	if b.har != nil {
+		if err := b.har.write(); err != nil {
+			k6ext.Panic(b.ctx, "recording HAR: %w", err)
+		}
+	}
+	b.harRoutersMu.RLock()
+	defer b.harRoutersMu.RUnlock()
+	for _, h := range b.harUpdates {
+		if err := h.write(); err != nil {
+			k6ext.Panic(b.ctx, "updating HAR: %w", err)
+		}
 	}
 }
 
-// Cookies is not implemented.
-func (b *BrowserContext) Cookies() ([]any, error) {
-	return nil, fmt.Errorf("BrowserContext.cookies() has not been implemented yet: %w", k6error.ErrFatal)
+// Cookies returns the cookies of this browser context. If URLs are
+// given, only the cookies that are sent to any of them are returned.
+func (b *BrowserContext) Cookies(urls ...string) ([]*api.Cookie, error) {
+	b.logger.Debugf("BrowserContext:Cookies", "bctxid:%v urls:%v", b.id, urls)
+
+	cookies, err := b.cookies()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterCookies(cookies, urls)
 }
 
 // ExposeBinding is not implemented.
@@ -267,6 +436,20 @@ func (b *BrowserContext) NewPage() (api.Page, error) {
 	return p, nil
 }
 
+// On subscribes to the browser context events with the handler.
+// The handler is called on the event loop until the browser context is closed
+// or the iteration ends.
+func (b *BrowserContext) On(event string, handler func(any) error) error {
+	switch event {
+	case EventBrowserContextExtensionWorker, EventBrowserContextServiceWorker:
+	default:
+		return fmt.Errorf("unknown browser context event: %q", event)
+	}
+	handleEvents(b.ctx, b.done, b, []string{event}, handler, b.logger)
+
+	return nil
+}
+
 // Pages returns a list of pages inside this browser context.
 func (b *BrowserContext) Pages() []api.Page {
 	pages := make([]api.Page, 1)
@@ -276,9 +459,49 @@ func (b *BrowserContext) Pages() []api.Page {
 	return pages
 }
 
-// Route is not implemented.
-func (b *BrowserContext) Route(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(b.ctx, "BrowserContext.route(url, handler) has not been implemented yet")
+// Route registers a handler that intercepts the requests of all the pages
+// in this browser context whose URL matches the given glob, regular
+// expression or predicate. Routes of a page take precedence over the
+// routes of its browser context.
+func (b *BrowserContext) Route(url goja.Value, handler api.RouteHandler) {
+	b.logger.Debugf("BrowserContext:Route", "bctxid:%v url:%v", b.id, url)
+
+	rh, err := newRouteHandler(b.ctx, b.done, url, handler)
+	if err != nil {
+		k6ext.Panic(b.ctx, "adding route: %w", err)
+	}
+
+	b.routesMu.Lock()
+	b.routes = append(b.routes, rh)
+	b.routesMu.Unlock()
+
+	for _, p := range b.getPages() {
+		if err := p.updateRequestInterception(); err != nil {
+			k6ext.Panic(b.ctx, "adding route in target ID %s: %w", p.targetID, err)
+		}
+	}
+}
+
+// RouteFromHAR answers the requests of all the pages in this browser
+// context whose URL matches the url option from the HAR file at path.
+func (b *BrowserContext) RouteFromHAR(path string, opts goja.Value) {
+	b.logger.Debugf("BrowserContext:RouteFromHAR", "bctxid:%v path:%q", b.id, path)
+
+	rh, router, err := newHARRouteHandler(b.ctx, path, opts)
+	if err != nil {
+		k6ext.Panic(b.ctx, "routing from HAR: %w", err)
+	}
+	b.addHARRouter(router)
+
+	b.routesMu.Lock()
+	b.routes = append(b.routes, rh)
+	b.routesMu.Unlock()
+
+	for _, p := range b.getPages() {
+		if err := p.updateRequestInterception(); err != nil {
+			k6ext.Panic(b.ctx, "routing from HAR in target ID %s: %w", p.targetID, err)
+		}
+	}
 }
 
 // SetDefaultNavigationTimeout sets the default navigation timeout in milliseconds.
@@ -349,14 +572,100 @@ func (b *BrowserContext) SetOffline(offline bool) {
 	}
 }
 
-// StorageState is not implemented.
-func (b *BrowserContext) StorageState(opts goja.Value) {
-	k6ext.Panic(b.ctx, "BrowserContext.storageState(opts) has not been implemented yet")
+// ServiceWorkers returns the service workers running in this browser context.
+func (b *BrowserContext) ServiceWorkers() []api.Worker {
+	b.workersMu.RLock()
+	defer b.workersMu.RUnlock()
+
+	workers := make([]api.Worker, 0, len(b.serviceWorkers))
+	for _, w := range b.serviceWorkers {
+		workers = append(workers, w)
+	}
+	return workers
+}
+
+// ExtensionWorkers returns the background pages and the service workers
+// of the extensions loaded in this browser context.
+func (b *BrowserContext) ExtensionWorkers() []api.Worker {
+	b.workersMu.RLock()
+	defer b.workersMu.RUnlock()
+
+	workers := make([]api.Worker, 0, len(b.backgroundWorkers))
+	for _, w := range b.backgroundWorkers {
+		workers = append(workers, w)
+	}
+	for _, w := range b.serviceWorkers {
+		if isExtensionURL(w.URL()) {
+			workers = append(workers, w)
+		}
+	}
+	return workers
+}
+
+// StorageState returns the cookies and the local storage of the origins of
+// the pages of this browser context. It also writes them to a file in JSON
+// if a path is given.
+func (b *BrowserContext) StorageState(opts goja.Value) (*api.StorageState, error) {
+	b.logger.Debugf("BrowserContext:StorageState", "bctxid:%v", b.id)
+
+	sopts := NewStorageStateOptions()
+	if err := sopts.Parse(b.ctx, opts); err != nil {
+		return nil, fmt.Errorf("parsing storage state options: %w", err)
+	}
+
+	cookies, err := b.cookies()
+	if err != nil {
+		return nil, err
+	}
+	state := &api.StorageState{
+		Cookies: cookies,
+		Origins: []*api.StorageStateOrigin{},
+	}
+	for origin, f := range b.framesByOrigin() {
+		items, err := localStorageOf(f)
+		if err != nil {
+			return nil, fmt.Errorf("getting local storage of %q: %w", origin, err)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		state.Origins = append(state.Origins, &api.StorageStateOrigin{
+			Origin:       origin,
+			LocalStorage: items,
+		})
+	}
+
+	if sopts.Path != "" {
+		if err := writeStorageState(sopts.Path, state); err != nil {
+			return nil, err
+		}
+	}
+
+	return state, nil
 }
 
-// Unroute is not implemented.
-func (b *BrowserContext) Unroute(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(b.ctx, "BrowserContext.unroute(url, handler) has not been implemented yet")
+// Unroute removes the route handlers registered for the URL.
+// If a handler is given, only that handler is removed.
+func (b *BrowserContext) Unroute(url goja.Value, handler goja.Value) {
+	b.logger.Debugf("BrowserContext:Unroute", "bctxid:%v url:%v", b.id, url)
+
+	b.routesMu.Lock()
+	routes := make([]*routeHandler, 0, len(b.routes))
+	for _, rh := range b.routes {
+		if rh.equals(url, handler) {
+			rh.close()
+			continue
+		}
+		routes = append(routes, rh)
+	}
+	b.routes = routes
+	b.routesMu.Unlock()
+
+	for _, p := range b.getPages() {
+		if err := p.updateRequestInterception(); err != nil {
+			k6ext.Panic(b.ctx, "removing route in target ID %s: %w", p.targetID, err)
+		}
+	}
 }
 
 // WaitForEvent waits for event.
@@ -459,52 +768,210 @@ func (b *BrowserContext) runWaitForEventHandler(
 	}
 }
 
+// getPages returns the pages that belong to this browser context.
+func (b *BrowserContext) getPages() []*Page {
+	var pages []*Page
+	for _, p := range b.browser.getPages() {
+		if p.browserCtx == b {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
+
+// addHARRouter adds a HAR router that records the requests of all the
+// pages. Its HAR file is updated when the browser context is closed.
+func (b *BrowserContext) addHARRouter(r *harRouter) {
+	if !r.opts.Update {
+		return
+	}
+
+	b.harRoutersMu.Lock()
+	defer b.harRoutersMu.Unlock()
+
+	b.harRouters = append(b.harRouters, r)
+	b.harUpdates = append(b.harUpdates, r.har)
+}
+
+// updateHAROnClose updates the HAR file when the browser context is closed.
+func (b *BrowserContext) updateHAROnClose(h *harRecorder) {
+	b.harRoutersMu.Lock()
+	defer b.harRoutersMu.Unlock()
+
+	b.harUpdates = append(b.harUpdates, h)
+}
+
+// recordHAR records a finished, failed or redirected request
+// in the HAR files of this browser context.
+func (b *BrowserContext) recordHAR(page *Page, req *Request, end time.Time) {
+	if b.har != nil {
+		b.har.record(page, req, end)
+	}
+
+	b.harRoutersMu.RLock()
+	defer b.harRoutersMu.RUnlock()
+
+	for _, r := range b.harRouters {
+		r.record(page, req, end)
+	}
+}
+
+func (b *BrowserContext) hasRoutes() bool {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	return len(b.routes) > 0
+}
+
+// routesFor returns the route handlers that might match the URL,
+// the most recently registered first.
+func (b *BrowserContext) routesFor(url string) []*routeHandler {
+	b.routesMu.RLock()
+	defer b.routesMu.RUnlock()
+
+	var routes []*routeHandler
+	for i := len(b.routes) - 1; i >= 0; i-- {
+		if b.routes[i].mightMatch(url) {
+			routes = append(routes, b.routes[i])
+		}
+	}
+
+	return routes
+}
+
 func (b *BrowserContext) getSession(id target.SessionID) *Session {
 	return b.browser.conn.getSession(id)
 }
 
-func (b *BrowserContext) addCookies(cookies goja.Value) error {
-	var cookieParams []network.CookieParam
-	if !gojaValueExists(cookies) {
-		return Error("cookies value is not set")
+// addServiceWorker registers the service worker and emits the serviceworker
+// event. It also emits the extensionworker event for the service workers of
+// the extensions.
+func (b *BrowserContext) addServiceWorker(id target.ID, w *Worker) {
+	b.workersMu.Lock()
+	b.serviceWorkers[id] = w
+	b.workersMu.Unlock()
+
+	b.emit(EventBrowserContextServiceWorker, w)
+	if isExtensionURL(w.URL()) {
+		b.emit(EventBrowserContextExtensionWorker, w)
 	}
+}
 
-	rt := b.vu.Runtime()
-	err := rt.ExportTo(cookies, &cookieParams)
-	if err != nil {
-		return fmt.Errorf("unable to export cookies value to cookieParams. %w", err)
+// addBackgroundWorker registers the background page of an extension
+// and emits the extensionworker event.
+func (b *BrowserContext) addBackgroundWorker(id target.ID, w *Worker) {
+	b.workersMu.Lock()
+	b.backgroundWorkers[id] = w
+	b.workersMu.Unlock()
+
+	b.emit(EventBrowserContextExtensionWorker, w)
+}
+
+// adoptWorkers moves the workers of the other browser context to this one.
+func (b *BrowserContext) adoptWorkers(other *BrowserContext) {
+	other.workersMu.Lock()
+	sws, bws := other.serviceWorkers, other.backgroundWorkers
+	other.serviceWorkers = make(map[target.ID]*Worker)
+	other.backgroundWorkers = make(map[target.ID]*Worker)
+	other.workersMu.Unlock()
+
+	b.workersMu.Lock()
+	defer b.workersMu.Unlock()
+	for id, w := range sws {
+		b.serviceWorkers[id] = w
+	}
+	for id, w := range bws {
+		b.backgroundWorkers[id] = w
 	}
+}
 
-	// Create new array of pointers to items in cookieParams
-	var cookieParamsPointers []*network.CookieParam
-	for i := 0; i < len(cookieParams); i++ {
-		cookieParam := cookieParams[i]
+// closeWorker unregisters the worker with the given target ID. It
+// returns false if the worker doesn't belong to this browser context.
+func (b *BrowserContext) closeWorker(id target.ID) bool {
+	b.workersMu.Lock()
+	w, ok := b.serviceWorkers[id]
+	delete(b.serviceWorkers, id)
+	if !ok {
+		w, ok = b.backgroundWorkers[id]
+		delete(b.backgroundWorkers, id)
+	}
+	b.workersMu.Unlock()
 
-		if cookieParam.Name == "" {
-			return fmt.Errorf("cookie name is not set. %#v", cookieParam)
-		}
+	if ok {
+		w.didClose()
+	}
+	return ok
+}
 
-		if cookieParam.Value == "" {
-			return fmt.Errorf("cookie value is not set. %#v", cookieParam)
-		}
+// isExtensionURL returns true if the URL belongs to an extension.
+func isExtensionURL(u string) bool {
+	return strings.HasPrefix(u, "chrome-extension://")
+}
 
-		// if URL is not set, both Domain and Path must be provided
-		if cookieParam.URL == "" {
-			if cookieParam.Domain == "" || cookieParam.Path == "" {
-				return fmt.Errorf(
-					"if cookie url is not provided, both domain and path must be specified. %#v",
-					cookieParam,
-				)
+// pagesOrigins returns the unique origins of the frames of the pages.
+func pagesOrigins(pages []*Page) []string {
+	var (
+		origins []string
+		seen    = make(map[string]bool)
+	)
+	for _, p := range pages {
+		for _, f := range p.Frames() {
+			origin, err := originOf(f.URL())
+			if err != nil || seen[origin] {
+				continue
 			}
+			seen[origin] = true
+			origins = append(origins, origin)
 		}
+	}
+
+	return origins
+}
 
-		cookieParamsPointers = append(cookieParamsPointers, &cookieParam)
+// storageExecutor returns the executor of the storage and cache commands.
+// The commands are sent through a page so that they apply to the storage
+// partition of this browser context. The browser session can't be used
+// since it applies them to the default browser context.
+func (b *BrowserContext) storageExecutor() (cdp.Executor, error) {
+	pages := b.getPages()
+	if len(pages) == 0 {
+		return nil, errors.New("browser context has no pages")
 	}
 
-	action := storage.SetCookies(cookieParamsPointers).WithBrowserContextID(b.id)
-	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
-		return fmt.Errorf("unable to execute SetCookies action: %w", err)
+	return pages[0].session, nil
+}
+
+// originOf returns the origin of the HTTP(S) URL.
+func originOf(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing origin %q: %w", rawURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("origin %q is not an HTTP(S) origin", rawURL)
 	}
 
-	return nil
+	return u.Scheme + "://" + u.Host, nil
+}
+
+func (b *BrowserContext) addCookies(cookies goja.Value) error {
+	if !gojaValueExists(cookies) {
+		return Error("cookies value is not set")
+	}
+
+	var cc []*api.Cookie
+	rt := b.vu.Runtime()
+	if err := rt.ExportTo(cookies, &cc); err != nil {
+		return fmt.Errorf("cookies must be an array of cookies: %w", err)
+	}
+	for _, c := range cc {
+		if c == nil {
+			return errors.New("cookie is not set")
+		}
+		if err := validateCookie(c); err != nil {
+			return err
+		}
+	}
+
+	return b.setCookies(cc)
 }
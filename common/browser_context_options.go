@@ -2,35 +2,51 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
+	"github.com/grafana/xk6-browser/api"
 	"github.com/grafana/xk6-browser/k6ext"
 
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
 	"github.com/dop251/goja"
 )
 
 // BrowserContextOptions stores browser context options.
 type BrowserContextOptions struct {
-	AcceptDownloads   bool              `js:"acceptDownloads"`
-	BypassCSP         bool              `js:"bypassCSP"`
-	ColorScheme       ColorScheme       `js:"colorScheme"`
-	DeviceScaleFactor float64           `js:"deviceScaleFactor"`
-	ExtraHTTPHeaders  map[string]string `js:"extraHTTPHeaders"`
-	Geolocation       *Geolocation      `js:"geolocation"`
-	HasTouch          bool              `js:"hasTouch"`
-	HttpCredentials   *Credentials      `js:"httpCredentials"`
-	IgnoreHTTPSErrors bool              `js:"ignoreHTTPSErrors"`
-	IsMobile          bool              `js:"isMobile"`
-	JavaScriptEnabled bool              `js:"javaScriptEnabled"`
-	Locale            string            `js:"locale"`
-	Offline           bool              `js:"offline"`
-	Permissions       []string          `js:"permissions"`
-	ReducedMotion     ReducedMotion     `js:"reducedMotion"`
-	Screen            *Screen           `js:"screen"`
-	TimezoneID        string            `js:"timezoneID"`
-	UserAgent         string            `js:"userAgent"`
-	VideosPath        string            `js:"videosPath"`
-	Viewport          *Viewport         `js:"viewport"`
+	AcceptDownloads   bool               `js:"acceptDownloads"`
+	BlockedRequests   *BlockedRequests   `js:"blockedRequests"`
+	BypassCSP         bool               `js:"bypassCSP"`
+	ColorScheme       ColorScheme        `js:"colorScheme"`
+	CPUThrottlingRate float64            `js:"cpuThrottlingRate"`
+	DeviceScaleFactor float64            `js:"deviceScaleFactor"`
+	ExtraHTTPHeaders  map[string]string  `js:"extraHTTPHeaders"`
+	Geolocation       *Geolocation       `js:"geolocation"`
+	HasTouch          bool               `js:"hasTouch"`
+	HttpCredentials   *Credentials       `js:"httpCredentials"`
+	IgnoreHTTPSErrors bool               `js:"ignoreHTTPSErrors"`
+	IsMobile          bool               `js:"isMobile"`
+	JavaScriptEnabled bool               `js:"javaScriptEnabled"`
+	Locale            string             `js:"locale"`
+	MetricsInterval   time.Duration      `js:"metricsInterval"`
+	NetworkConditions *NetworkConditions `js:"networkConditions"`
+	Offline           bool               `js:"offline"`
+	Permissions       []string           `js:"permissions"`
+	Proxy             *Proxy             `js:"proxy"`
+	RecordHAR         *RecordHAROptions  `js:"recordHar"`
+	ReducedMotion     ReducedMotion      `js:"reducedMotion"`
+	Screen            *Screen            `js:"screen"`
+	ServiceWorkers    ServiceWorkers     `js:"serviceWorkers"`
+	StorageState      *api.StorageState  `js:"storageState"`
+	TimezoneID        string             `js:"timezoneID"`
+	UserAgent         string             `js:"userAgent"`
+	UserTimings       bool               `js:"userTimings"`
+	VideosPath        string             `js:"videosPath"`
+	Viewport          *Viewport          `js:"viewport"`
 }
 
 // NewBrowserContextOptions creates a default set of browser context options.
@@ -44,6 +60,7 @@ func NewBrowserContextOptions() *BrowserContextOptions {
 		Permissions:       []string{},
 		ReducedMotion:     ReducedMotionNoPreference,
 		Screen:            &Screen{Width: DefaultScreenWidth, Height: DefaultScreenHeight},
+		ServiceWorkers:    ServiceWorkersAllow,
 		Viewport:          &Viewport{Width: DefaultScreenWidth, Height: DefaultScreenHeight},
 	}
 }
@@ -56,6 +73,12 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 			switch k {
 			case "acceptDownloads":
 				b.AcceptDownloads = opts.Get(k).ToBoolean()
+			case "blockedRequests":
+				blocked := NewBlockedRequests()
+				if err := blocked.Parse(ctx, opts.Get(k)); err != nil {
+					return err
+				}
+				b.BlockedRequests = blocked
 			case "bypassCSP":
 				b.BypassCSP = opts.Get(k).ToBoolean()
 			case "colorScheme":
@@ -67,6 +90,12 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 				default:
 					b.ColorScheme = ColorSchemeNoPreference
 				}
+			case "cpuThrottlingRate":
+				rate := opts.Get(k).ToFloat()
+				if rate < 1 {
+					return fmt.Errorf(`invalid cpuThrottlingRate "%.2f": precondition 1 <= RATE failed`, rate)
+				}
+				b.CPUThrottlingRate = rate
 			case "deviceScaleFactor":
 				b.DeviceScaleFactor = opts.Get(k).ToFloat()
 			case "extraHTTPHeaders":
@@ -96,6 +125,18 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 				b.JavaScriptEnabled = opts.Get(k).ToBoolean()
 			case "locale":
 				b.Locale = opts.Get(k).String()
+			case "metricsInterval":
+				interval := time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+				if interval < 0 {
+					return fmt.Errorf("invalid metricsInterval %q: must not be negative", interval)
+				}
+				b.MetricsInterval = interval
+			case "networkConditions":
+				conditions := NewNetworkConditions()
+				if err := conditions.Parse(ctx, opts.Get(k)); err != nil {
+					return err
+				}
+				b.NetworkConditions = conditions
 			case "offline":
 				b.Offline = opts.Get(k).ToBoolean()
 			case "permissions":
@@ -104,6 +145,18 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 						b.Permissions = append(b.Permissions, fmt.Sprintf("%v", p))
 					}
 				}
+			case "proxy":
+				proxy := NewProxy()
+				if err := proxy.Parse(ctx, opts.Get(k)); err != nil {
+					return err
+				}
+				b.Proxy = proxy
+			case "recordHar":
+				recordHAR := NewRecordHAROptions()
+				if err := recordHAR.Parse(ctx, opts.Get(k)); err != nil {
+					return err
+				}
+				b.RecordHAR = recordHAR
 			case "reducedMotion":
 				switch ReducedMotion(opts.Get(k).String()) {
 				case "reduce":
@@ -117,10 +170,25 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 					return err
 				}
 				b.Screen = screen
+			case "serviceWorkers":
+				switch sw := ServiceWorkers(opts.Get(k).String()); sw {
+				case ServiceWorkersAllow, ServiceWorkersBlock:
+					b.ServiceWorkers = sw
+				default:
+					return fmt.Errorf(`invalid serviceWorkers %q: must be "allow" or "block"`, sw)
+				}
+			case "storageState":
+				state, err := parseStorageState(ctx, opts.Get(k))
+				if err != nil {
+					return err
+				}
+				b.StorageState = state
 			case "timezoneID":
 				b.TimezoneID = opts.Get(k).String()
 			case "userAgent":
 				b.UserAgent = opts.Get(k).String()
+			case "userTimings":
+				b.UserTimings = opts.Get(k).ToBoolean()
 			case "viewport":
 				viewport := &Viewport{}
 				if err := viewport.Parse(ctx, opts.Get(k).ToObject(rt)); err != nil {
@@ -132,3 +200,233 @@ func (b *BrowserContextOptions) Parse(ctx context.Context, opts goja.Value) erro
 	}
 	return nil
 }
+
+// Reasons for blocking a request by the blockedRequests option.
+const (
+	blockedRequestReasonResourceType = "resource_type"
+	blockedRequestReasonURL          = "url"
+)
+
+// BlockedRequests are the requests that a browser context blocks
+// by their resource type, such as "image" or "font", or by their URL.
+type BlockedRequests struct {
+	ResourceTypes []string `js:"resourceTypes"`
+	URLs          []string `js:"urls"`
+
+	resourceTypes map[network.ResourceType]bool
+	urlMatchers   []urlMatcher
+}
+
+// NewBlockedRequests returns a new BlockedRequests.
+func NewBlockedRequests() *BlockedRequests {
+	return &BlockedRequests{
+		resourceTypes: make(map[network.ResourceType]bool),
+	}
+}
+
+// Parse parses the blocked requests option. The URLs can be
+// glob pattern strings or regular expressions.
+func (b *BlockedRequests) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "resourceTypes":
+			var types []string
+			if err := rt.ExportTo(obj.Get(k), &types); err != nil {
+				return fmt.Errorf("parsing blocked resource types: %w", err)
+			}
+			for _, typ := range types {
+				rtyp, ok := parseResourceType(typ)
+				if !ok {
+					return fmt.Errorf("unknown resource type %q", typ)
+				}
+				b.ResourceTypes = append(b.ResourceTypes, typ)
+				b.resourceTypes[rtyp] = true
+			}
+		case "urls":
+			v := obj.Get(k)
+			if !gojaValueExists(v) || v.ExportType().Kind() != reflect.Slice {
+				return fmt.Errorf("blocked URLs must be an array, got %q", v)
+			}
+			urls := v.ToObject(rt)
+			for _, i := range urls.Keys() {
+				u := urls.Get(i)
+				if _, ok := goja.AssertFunction(u); ok {
+					return errors.New("blocked URL must be a string or a regular expression, got a function")
+				}
+				matcher, err := newURLMatcher(rt, u)
+				if err != nil {
+					return fmt.Errorf("parsing blocked URL: %w", err)
+				}
+				b.URLs = append(b.URLs, u.String())
+				b.urlMatchers = append(b.urlMatchers, matcher)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reason returns why a request with the given resource type and URL
+// is blocked, or an empty string if the request is not blocked.
+func (b *BlockedRequests) reason(typ network.ResourceType, url string) string {
+	if b == nil {
+		return ""
+	}
+	if b.resourceTypes[typ] {
+		return blockedRequestReasonResourceType
+	}
+	for _, match := range b.urlMatchers {
+		if match(url) {
+			return blockedRequestReasonURL
+		}
+	}
+
+	return ""
+}
+
+// parseResourceType returns the CDP resource type for
+// the case insensitive resource type name.
+func parseResourceType(name string) (network.ResourceType, bool) {
+	for _, typ := range []network.ResourceType{
+		network.ResourceTypeDocument,
+		network.ResourceTypeStylesheet,
+		network.ResourceTypeImage,
+		network.ResourceTypeMedia,
+		network.ResourceTypeFont,
+		network.ResourceTypeScript,
+		network.ResourceTypeTextTrack,
+		network.ResourceTypeXHR,
+		network.ResourceTypeFetch,
+		network.ResourceTypePrefetch,
+		network.ResourceTypeEventSource,
+		network.ResourceTypeWebSocket,
+		network.ResourceTypeManifest,
+		network.ResourceTypeSignedExchange,
+		network.ResourceTypePing,
+		network.ResourceTypeCSPViolationReport,
+		network.ResourceTypePreflight,
+		network.ResourceTypeOther,
+	} {
+		if strings.EqualFold(typ.String(), name) {
+			return typ, true
+		}
+	}
+
+	return "", false
+}
+
+// BrowserContextClearStorageOptions are the options of BrowserContext.ClearStorage.
+type BrowserContextClearStorageOptions struct {
+	Origins []string `js:"origins"`
+	Types   []string `js:"types"`
+}
+
+// clearableStorageTypes are the storage types that ClearStorage can clear.
+var clearableStorageTypes = []storage.Type{ //nolint:gochecknoglobals
+	storage.TypeCookies,
+	storage.TypeLocalStorage,
+	storage.TypeIndexeddb,
+	storage.TypeCacheStorage,
+	storage.TypeServiceWorkers,
+}
+
+// NewBrowserContextClearStorageOptions returns the default options
+// that clear all the storage types.
+func NewBrowserContextClearStorageOptions() *BrowserContextClearStorageOptions {
+	types := make([]string, 0, len(clearableStorageTypes))
+	for _, typ := range clearableStorageTypes {
+		types = append(types, typ.String())
+	}
+
+	return &BrowserContextClearStorageOptions{Types: types}
+}
+
+// Parse parses the clear storage options.
+func (o *BrowserContextClearStorageOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "origins":
+			if err := rt.ExportTo(obj.Get(k), &o.Origins); err != nil {
+				return fmt.Errorf("parsing origins: %w", err)
+			}
+		case "types":
+			var types []string
+			if err := rt.ExportTo(obj.Get(k), &types); err != nil {
+				return fmt.Errorf("parsing storage types: %w", err)
+			}
+			for _, typ := range types {
+				if !isClearableStorageType(typ) {
+					return fmt.Errorf("unknown storage type %q", typ)
+				}
+			}
+			o.Types = types
+		}
+	}
+
+	return nil
+}
+
+func isClearableStorageType(typ string) bool {
+	for _, t := range clearableStorageTypes {
+		if t.String() == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BrowserContextClearCookiesOptions are the options of BrowserContext.ClearCookies.
+type BrowserContextClearCookiesOptions struct {
+	Name   string `js:"name"`
+	Domain string `js:"domain"`
+	Path   string `js:"path"`
+}
+
+// NewBrowserContextClearCookiesOptions returns the default options that clear all the cookies.
+func NewBrowserContextClearCookiesOptions() *BrowserContextClearCookiesOptions {
+	return &BrowserContextClearCookiesOptions{}
+}
+
+// Parse parses the clear cookies options.
+func (o *BrowserContextClearCookiesOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "name":
+			o.Name = obj.Get(k).String()
+		case "domain":
+			o.Domain = obj.Get(k).String()
+		case "path":
+			o.Path = obj.Get(k).String()
+		}
+	}
+
+	return nil
+}
+
+// selective returns true if only some of the cookies should be cleared.
+func (o *BrowserContextClearCookiesOptions) selective() bool {
+	return o.Name != "" || o.Domain != "" || o.Path != ""
+}
+
+// matches returns true if the cookie matches all of the given options.
+func (o *BrowserContextClearCookiesOptions) matches(c *api.Cookie) bool {
+	return (o.Name == "" || o.Name == c.Name) &&
+		(o.Domain == "" || o.Domain == c.Domain) &&
+		(o.Path == "" || o.Path == c.Path)
+}
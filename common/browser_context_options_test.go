@@ -2,10 +2,13 @@ package common
 
 import (
 	"testing"
+	"time"
 
 	"github.com/grafana/xk6-browser/k6ext/k6test"
 
+	"github.com/chromedp/cdproto/network"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBrowserContextOptionsPermissions(t *testing.T) {
@@ -21,3 +24,108 @@ func TestBrowserContextOptionsPermissions(t *testing.T) {
 	assert.Len(t, opts.Permissions, 2)
 	assert.Equal(t, opts.Permissions, []string{"camera", "microphone"})
 }
+
+func TestBrowserContextOptionsCPUThrottlingRate(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	var opts BrowserContextOptions
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"cpuThrottlingRate": 4,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, opts.CPUThrottlingRate)
+
+	err = opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"cpuThrottlingRate": 0.5,
+	}))
+	assert.ErrorContains(t, err, "invalid cpuThrottlingRate")
+}
+
+func TestBrowserContextOptionsBlockedRequests(t *testing.T) {
+	vu := k6test.NewVU(t)
+	rt := vu.Runtime()
+
+	var opts BrowserContextOptions
+	blocked, err := rt.RunString(`({
+		resourceTypes: ['image', 'Font'],
+		urls: ['**/analytics.js', /ads\.example\.com/],
+	})`)
+	require.NoError(t, err)
+	err = opts.Parse(vu.Context(), rt.ToValue(map[string]any{"blockedRequests": blocked}))
+	require.NoError(t, err)
+	require.NotNil(t, opts.BlockedRequests)
+
+	b := opts.BlockedRequests
+	assert.Equal(t, []string{"image", "Font"}, b.ResourceTypes)
+	assert.Equal(t, "resource_type", b.reason(network.ResourceTypeImage, "http://host.test/a.png"))
+	assert.Equal(t, "resource_type", b.reason(network.ResourceTypeFont, "http://host.test/a.woff"))
+	assert.Equal(t, "url", b.reason(network.ResourceTypeScript, "http://host.test/js/analytics.js"))
+	assert.Equal(t, "url", b.reason(network.ResourceTypeXHR, "https://ads.example.com/track"))
+	assert.Empty(t, b.reason(network.ResourceTypeDocument, "http://host.test/"))
+
+	err = opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"blockedRequests": map[string]any{"resourceTypes": []string{"gif"}},
+	}))
+	assert.ErrorContains(t, err, `unknown resource type "gif"`)
+
+	for _, urls := range []string{`'**/analytics.js'`, `null`, `undefined`} {
+		blocked, err := rt.RunString(`({ urls: ` + urls + ` })`)
+		require.NoError(t, err)
+		err = opts.Parse(vu.Context(), rt.ToValue(map[string]any{"blockedRequests": blocked}))
+		assert.ErrorContains(t, err, "blocked URLs must be an array", urls)
+	}
+}
+
+func TestBrowserContextOptionsServiceWorkers(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	assert.Equal(t, ServiceWorkersAllow, opts.ServiceWorkers)
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"serviceWorkers": "block",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, ServiceWorkersBlock, opts.ServiceWorkers)
+
+	err = opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"serviceWorkers": "deny",
+	}))
+	assert.ErrorContains(t, err, `invalid serviceWorkers "deny"`)
+}
+
+func TestBrowserContextOptionsMetricsInterval(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextOptions()
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"metricsInterval": 5000,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, opts.MetricsInterval)
+
+	err = opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"metricsInterval": -1,
+	}))
+	assert.ErrorContains(t, err, "invalid metricsInterval")
+}
+
+func TestBrowserContextClearStorageOptionsParse(t *testing.T) {
+	vu := k6test.NewVU(t)
+
+	opts := NewBrowserContextClearStorageOptions()
+	assert.Equal(t, []string{"cookies", "local_storage", "indexeddb", "cache_storage", "service_workers"}, opts.Types)
+
+	err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"origins": []string{"https://example.com"},
+		"types":   []string{"local_storage", "indexeddb"},
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com"}, opts.Origins)
+	assert.Equal(t, []string{"local_storage", "indexeddb"}, opts.Types)
+
+	err = opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"types": []string{"websql"},
+	}))
+	assert.ErrorContains(t, err, `unknown storage type "websql"`)
+}
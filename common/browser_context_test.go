@@ -42,3 +42,14 @@ func TestNewBrowserContext(t *testing.T) {
 		assert.True(t, webVitalInitScriptFound, "WebVitalInitScript was not initialized in the context")
 	})
 }
+
+func TestOriginOf(t *testing.T) {
+	t.Parallel()
+
+	origin, err := originOf("https://example.com:8443/path?q=1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com:8443", origin)
+
+	_, err = originOf("about:blank")
+	assert.ErrorContains(t, err, "not an HTTP(S) origin")
+}
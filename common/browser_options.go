@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -23,7 +25,9 @@ const (
 
 	optArgs              = "K6_BROWSER_ARGS"
 	optDebug             = "K6_BROWSER_DEBUG"
+	optEmbeddedProxy     = "K6_BROWSER_EMBEDDED_PROXY"
 	optExecutablePath    = "K6_BROWSER_EXECUTABLE_PATH"
+	optExtensions        = "K6_BROWSER_EXTENSIONS"
 	optHeadless          = "K6_BROWSER_HEADLESS"
 	optIgnoreDefaultArgs = "K6_BROWSER_IGNORE_DEFAULT_ARGS"
 	optLogCategoryFilter = "K6_BROWSER_LOG_CATEGORY_FILTER"
@@ -32,9 +36,13 @@ const (
 
 // BrowserOptions stores browser options.
 type BrowserOptions struct {
-	Args              []string
-	Debug             bool
-	ExecutablePath    string
+	Args           []string
+	Debug          bool
+	EmbeddedProxy  bool
+	ExecutablePath string
+	// Extensions are the directories of the unpacked extensions
+	// that are loaded when the browser is launched.
+	Extensions        []string
 	Headless          bool
 	IgnoreDefaultArgs []string
 	LogCategoryFilter string
@@ -87,7 +95,9 @@ func (bo *BrowserOptions) Parse( //nolint:cyclop
 	envOpts := [...]string{
 		optArgs,
 		optDebug,
+		optEmbeddedProxy,
 		optExecutablePath,
+		optExtensions,
 		optHeadless,
 		optIgnoreDefaultArgs,
 		optLogCategoryFilter,
@@ -109,8 +119,12 @@ func (bo *BrowserOptions) Parse( //nolint:cyclop
 			bo.Args = parseListOpt(ev)
 		case optDebug:
 			bo.Debug, err = parseBoolOpt(e, ev)
+		case optEmbeddedProxy:
+			bo.EmbeddedProxy, err = parseBoolOpt(e, ev)
 		case optExecutablePath:
 			bo.ExecutablePath = ev
+		case optExtensions:
+			bo.Extensions, err = parseExtensionsOpt(e, ev)
 		case optHeadless:
 			bo.Headless, err = parseBoolOpt(e, ev)
 		case optIgnoreDefaultArgs:
@@ -135,7 +149,9 @@ func (bo *BrowserOptions) shouldIgnoreIfBrowserIsRemote(opt string) bool {
 
 	shouldIgnoreIfBrowserIsRemote := map[string]struct{}{
 		optArgs:              {},
+		optEmbeddedProxy:     {},
 		optExecutablePath:    {},
+		optExtensions:        {},
 		optHeadless:          {},
 		optIgnoreDefaultArgs: {},
 	}
@@ -162,6 +178,32 @@ func parseTimeOpt(k, v string) (time.Duration, error) {
 	return t, nil
 }
 
+// parseExtensionsOpt parses the extension directories as absolute paths,
+// since the browser resolves the relative paths from its own directory.
+func parseExtensionsOpt(k, v string) ([]string, error) {
+	var dirs []string
+	for _, dir := range parseListOpt(v) {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, fmt.Errorf("%s: resolving extension directory %q: %w", k, dir, err)
+		}
+		fi, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		if !fi.IsDir() {
+			return nil, fmt.Errorf("%s: %q is not an unpacked extension directory", k, dir)
+		}
+		dirs = append(dirs, abs)
+	}
+
+	return dirs, nil
+}
+
 func parseListOpt(v string) []string {
 	elems := strings.Split(v, ",")
 	// If last element is a void string,
@@ -1,6 +1,7 @@
 package common
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -21,6 +22,8 @@ func TestBrowserOptionsParse(t *testing.T) { //nolint:gocognit
 		Timeout:           DefaultTimeout,
 	}
 
+	extDir := t.TempDir()
+
 	noopEnvLookuper := func(string) (string, bool) {
 		return "", false
 	}
@@ -62,8 +65,12 @@ func TestBrowserOptionsParse(t *testing.T) { //nolint:gocognit
 				// disallow changing the following opts
 				case optArgs:
 					return "any", true
+				case optEmbeddedProxy:
+					return "true", true
 				case optExecutablePath:
 					return "something else", true
+				case optExtensions:
+					return "any", true
 				case optHeadless:
 					return "false", true
 				case optIgnoreDefaultArgs:
@@ -169,6 +176,33 @@ func TestBrowserOptionsParse(t *testing.T) { //nolint:gocognit
 				assert.Equal(t, "cmd/somewhere", lo.ExecutablePath)
 			},
 		},
+		"extensions": {
+			opts: map[string]any{
+				"type": "chromium",
+			},
+			envLookupper: func(k string) (string, bool) {
+				if k == optExtensions {
+					return extDir + ",", true
+				}
+				return "", false
+			},
+			assert: func(tb testing.TB, lo *BrowserOptions) {
+				tb.Helper()
+				assert.Equal(t, []string{extDir}, lo.Extensions)
+			},
+		},
+		"extensions_err": {
+			opts: map[string]any{
+				"type": "chromium",
+			},
+			envLookupper: func(k string) (string, bool) {
+				if k == optExtensions {
+					return filepath.Join(extDir, "missing"), true
+				}
+				return "", false
+			},
+			err: "K6_BROWSER_EXTENSIONS",
+		},
 		"headless": {
 			opts: map[string]any{
 				"type": "chromium",
@@ -228,6 +262,33 @@ func TestBrowserOptionsParse(t *testing.T) { //nolint:gocognit
 				assert.Equal(t, "**", lo.LogCategoryFilter)
 			},
 		},
+		"embedded_proxy": {
+			opts: map[string]any{
+				"type": "chromium",
+			},
+			envLookupper: func(k string) (string, bool) {
+				if k == optEmbeddedProxy {
+					return "true", true
+				}
+				return "", false
+			},
+			assert: func(tb testing.TB, lo *BrowserOptions) {
+				tb.Helper()
+				assert.True(t, lo.EmbeddedProxy)
+			},
+		},
+		"embedded_proxy_err": {
+			opts: map[string]any{
+				"type": "chromium",
+			},
+			envLookupper: func(k string) (string, bool) {
+				if k == optEmbeddedProxy {
+					return "ABC", true
+				}
+				return "", false
+			},
+			err: "K6_BROWSER_EMBEDDED_PROXY should be a boolean",
+		},
 		"timeout": {
 			opts: map[string]any{
 				"type": "chromium",
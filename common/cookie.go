@@ -0,0 +1,205 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/storage"
+)
+
+// cookies returns all the cookies of this browser context.
+func (b *BrowserContext) cookies() ([]*api.Cookie, error) {
+	action := storage.GetCookies().WithBrowserContextID(b.id)
+	cc, err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn))
+	if err != nil {
+		return nil, fmt.Errorf("getting cookies: %w", err)
+	}
+	cookies := make([]*api.Cookie, 0, len(cc))
+	for _, c := range cc {
+		cookies = append(cookies, cookieFromNetwork(c))
+	}
+
+	return cookies, nil
+}
+
+// setCookies adds the cookies to this browser context.
+func (b *BrowserContext) setCookies(cookies []*api.Cookie) error {
+	if len(cookies) == 0 {
+		return nil
+	}
+	params := make([]*network.CookieParam, 0, len(cookies))
+	for _, c := range cookies {
+		params = append(params, cookieParamFromCookie(c))
+	}
+	action := storage.SetCookies(params).WithBrowserContextID(b.id)
+	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+		return fmt.Errorf("setting cookies: %w", err)
+	}
+
+	return nil
+}
+
+// deleteCookie deletes the cookie from this browser context.
+// CDP can only delete a single cookie through a page, so the cookie
+// is expired instead if the browser context has no pages.
+func (b *BrowserContext) deleteCookie(c *api.Cookie) error {
+	if pages := b.getPages(); len(pages) > 0 {
+		action := network.DeleteCookies(c.Name).WithDomain(c.Domain).WithPath(c.Path)
+		if err := action.Do(cdp.WithExecutor(b.ctx, pages[0].session)); err != nil {
+			return fmt.Errorf("deleting cookie %q: %w", c.Name, err)
+		}
+		return nil
+	}
+
+	expired := *c
+	expired.Expires = 1
+	action := storage.SetCookies([]*network.CookieParam{cookieParamFromCookie(&expired)}).WithBrowserContextID(b.id)
+	if err := action.Do(cdp.WithExecutor(b.ctx, b.browser.conn)); err != nil {
+		return fmt.Errorf("deleting cookie %q: %w", c.Name, err)
+	}
+
+	return nil
+}
+
+// validateCookie returns an error if the cookie can't be added.
+func validateCookie(c *api.Cookie) error {
+	if c.Name == "" {
+		return errors.New("cookie name is not set")
+	}
+	if c.Value == "" {
+		return fmt.Errorf("cookie %q value is not set", c.Name)
+	}
+	if c.URL == "" && (c.Domain == "" || c.Path == "") {
+		return fmt.Errorf("cookie %q must have a url, or both a domain and a path", c.Name)
+	}
+	if c.URL != "" && (c.Domain != "" || c.Path != "") {
+		return fmt.Errorf("cookie %q must have either a url, or a domain and a path, not both", c.Name)
+	}
+	if c.URL != "" {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return fmt.Errorf("parsing cookie %q url: %w", c.Name, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("cookie %q url %q must be an http(s) URL", c.Name, c.URL)
+		}
+	}
+	switch network.CookieSameSite(c.SameSite) {
+	case "", network.CookieSameSiteStrict, network.CookieSameSiteLax, network.CookieSameSiteNone:
+	default:
+		return fmt.Errorf(`cookie %q sameSite must be "Strict", "Lax" or "None", got %q`, c.Name, c.SameSite)
+	}
+	if c.Expires != -1 && c.Expires < 0 {
+		return fmt.Errorf("cookie %q expires must be -1 for a session cookie, or a Unix time in seconds", c.Name)
+	}
+
+	return nil
+}
+
+// filterCookies returns the cookies that are sent to any of the URLs.
+func filterCookies(cookies []*api.Cookie, urls []string) ([]*api.Cookie, error) {
+	if len(urls) == 0 {
+		return cookies, nil
+	}
+	parsed := make([]*url.URL, 0, len(urls))
+	for _, u := range urls {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("parsing url %q: %w", u, err)
+		}
+		parsed = append(parsed, pu)
+	}
+
+	var filtered []*api.Cookie
+	for _, c := range cookies {
+		for _, u := range parsed {
+			if cookieMatchesURL(c, u) {
+				filtered = append(filtered, c)
+				break
+			}
+		}
+	}
+
+	return filtered, nil
+}
+
+// cookieMatchesURL returns true if the cookie is sent to the URL.
+func cookieMatchesURL(c *api.Cookie, u *url.URL) bool {
+	var (
+		host   = u.Hostname()
+		domain = c.Domain
+	)
+	if !strings.HasPrefix(domain, ".") {
+		domain = "." + domain
+	}
+	if !strings.HasSuffix("."+host, domain) {
+		return false
+	}
+	if !cookiePathMatches(c.Path, u.Path) {
+		return false
+	}
+	// Secure cookies are also sent to the local hosts over HTTP.
+	if u.Scheme != "https" && c.Secure && host != "localhost" && host != "127.0.0.1" {
+		return false
+	}
+
+	return true
+}
+
+// cookiePathMatches returns true if the request path is
+// within the cookie path, as defined in RFC 6265 5.1.4.
+func cookiePathMatches(cookiePath, path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if cookiePath == "" || cookiePath == path {
+		return true
+	}
+	if !strings.HasPrefix(path, cookiePath) {
+		return false
+	}
+
+	return strings.HasSuffix(cookiePath, "/") || path[len(cookiePath)] == '/'
+}
+
+func cookieFromNetwork(c *network.Cookie) *api.Cookie {
+	return &api.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Expires:  c.Expires,
+		HTTPOnly: c.HTTPOnly,
+		Secure:   c.Secure,
+		SameSite: c.SameSite.String(),
+	}
+}
+
+func cookieParamFromCookie(c *api.Cookie) *network.CookieParam {
+	p := &network.CookieParam{
+		Name:     c.Name,
+		Value:    c.Value,
+		URL:      c.URL,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		HTTPOnly: c.HTTPOnly,
+		Secure:   c.Secure,
+		SameSite: network.CookieSameSite(c.SameSite),
+	}
+	// Session cookies don't expire.
+	if c.Expires > 0 {
+		sec := int64(c.Expires)
+		nsec := int64((c.Expires - float64(sec)) * float64(time.Second))
+		expires := cdp.TimeSinceEpoch(time.Unix(sec, nsec))
+		p.Expires = &expires
+	}
+
+	return p
+}
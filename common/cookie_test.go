@@ -0,0 +1,141 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCookie(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cookie  api.Cookie
+		wantErr string
+	}{
+		{
+			name:   "url",
+			cookie: api.Cookie{Name: "a", Value: "b", URL: "https://example.com"},
+		},
+		{
+			name:   "domain_path",
+			cookie: api.Cookie{Name: "a", Value: "b", Domain: "example.com", Path: "/", SameSite: "Lax"},
+		},
+		{
+			name:    "missing_name",
+			cookie:  api.Cookie{Value: "b", URL: "https://example.com"},
+			wantErr: "cookie name is not set",
+		},
+		{
+			name:    "missing_path",
+			cookie:  api.Cookie{Name: "a", Value: "b", Domain: "example.com"},
+			wantErr: "must have a url, or both a domain and a path",
+		},
+		{
+			name:    "url_and_domain",
+			cookie:  api.Cookie{Name: "a", Value: "b", URL: "https://example.com", Domain: "example.com"},
+			wantErr: "not both",
+		},
+		{
+			name:    "url_not_http",
+			cookie:  api.Cookie{Name: "a", Value: "b", URL: "about:blank"},
+			wantErr: "must be an http(s) URL",
+		},
+		{
+			name:    "invalid_same_site",
+			cookie:  api.Cookie{Name: "a", Value: "b", URL: "https://example.com", SameSite: "lax"},
+			wantErr: `sameSite must be "Strict", "Lax" or "None", got "lax"`,
+		},
+		{
+			name:    "invalid_expires",
+			cookie:  api.Cookie{Name: "a", Value: "b", URL: "https://example.com", Expires: -2},
+			wantErr: "expires must be -1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateCookie(&tt.cookie)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFilterCookies(t *testing.T) {
+	t.Parallel()
+
+	var (
+		root   = &api.Cookie{Name: "root", Domain: "example.com", Path: "/"}
+		sub    = &api.Cookie{Name: "sub", Domain: ".example.com", Path: "/"}
+		admin  = &api.Cookie{Name: "admin", Domain: "example.com", Path: "/admin"}
+		secure = &api.Cookie{Name: "secure", Domain: "example.com", Path: "/", Secure: true}
+		other  = &api.Cookie{Name: "other", Domain: "other.com", Path: "/"}
+		all    = []*api.Cookie{root, sub, admin, secure, other}
+	)
+
+	tests := []struct {
+		name string
+		urls []string
+		want []*api.Cookie
+	}{
+		{name: "no_urls", want: all},
+		{name: "https", urls: []string{"https://example.com/"}, want: []*api.Cookie{root, sub, secure}},
+		{name: "http", urls: []string{"http://example.com"}, want: []*api.Cookie{root, sub}},
+		{name: "subdomain", urls: []string{"https://www.example.com/"}, want: []*api.Cookie{root, sub, secure}},
+		{name: "path", urls: []string{"https://example.com/admin/users"}, want: []*api.Cookie{root, sub, admin, secure}},
+		{name: "path_prefix", urls: []string{"https://example.com/administrator"}, want: []*api.Cookie{root, sub, secure}},
+		{
+			name: "many",
+			urls: []string{"http://example.com/", "https://other.com/"},
+			want: []*api.Cookie{root, sub, other},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := filterCookies(all, tt.urls)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBrowserContextClearCookiesOptionsMatches(t *testing.T) {
+	t.Parallel()
+
+	c := &api.Cookie{Name: "session", Domain: "example.com", Path: "/"}
+
+	assert.False(t, (&BrowserContextClearCookiesOptions{}).selective())
+	assert.True(t, (&BrowserContextClearCookiesOptions{Name: "session"}).matches(c))
+	assert.True(t, (&BrowserContextClearCookiesOptions{Name: "session", Domain: "example.com"}).matches(c))
+	assert.False(t, (&BrowserContextClearCookiesOptions{Name: "session", Path: "/admin"}).matches(c))
+	assert.False(t, (&BrowserContextClearCookiesOptions{Domain: "other.com"}).matches(c))
+}
+
+func TestCookieParamFromCookie(t *testing.T) {
+	t.Parallel()
+
+	p := cookieParamFromCookie(&api.Cookie{
+		Name: "a", Value: "b", Domain: "example.com", Path: "/", Expires: -1, SameSite: "Lax",
+	})
+	assert.Nil(t, p.Expires, "session cookies should not expire")
+	assert.Equal(t, network.CookieSameSiteLax, p.SameSite)
+
+	p = cookieParamFromCookie(&api.Cookie{Name: "a", Value: "b", Domain: "example.com", Expires: 1700000000.5})
+	require.NotNil(t, p.Expires)
+	assert.Equal(t, time.Unix(1700000000, int64(500*time.Millisecond)), p.Expires.Time())
+}
@@ -15,8 +15,10 @@ const (
 
 	// BrowserContext
 
-	EventBrowserContextClose string = "close"
-	EventBrowserContextPage  string = "page"
+	EventBrowserContextClose           string = "close"
+	EventBrowserContextExtensionWorker string = "extensionworker"
+	EventBrowserContextPage            string = "page"
+	EventBrowserContextServiceWorker   string = "serviceworker"
 
 	// Connection
 
@@ -39,6 +41,7 @@ const (
 	EventPageFrameDetached   string = "framedetached"
 	EventPageFrameNavigated  string = "framenavigated"
 	EventPageError           string = "pageerror"
+	EventPageEventSource     string = "eventsource"
 	EventPagePopup           string = "popup"
 	EventPageRequest         string = "request"
 	EventPageRequestFailed   string = "requestfailed"
@@ -51,6 +54,13 @@ const (
 
 	EventSessionClosed string = "close"
 
+	// WebSocket
+
+	EventWebSocketClose         string = "close"
+	EventWebSocketFrameReceived string = "framereceived"
+	EventWebSocketFrameSent     string = "framesent"
+	EventWebSocketError         string = "socketerror"
+
 	// Worker
 
 	EventWorkerClose string = "close"
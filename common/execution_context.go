@@ -184,7 +184,7 @@ func (e *ExecutionContext) eval(
 			if err != nil {
 				return nil, fmt.Errorf("converting argument %q "+
 					"in execution context ID %d and frame ID %v: %w",
-					arg, e.id, e.Frame().ID(), err)
+					arg, e.id, e.fid, err)
 			}
 			arguments = append(arguments, result)
 		}
@@ -1408,12 +1408,12 @@ func (f *Frame) selectOption(selector string, values goja.Value, opts *FrameSele
 }
 
 // SetContent replaces the entire HTML document content.
-func (f *Frame) SetContent(html string, opts goja.Value) {
+func (f *Frame) SetContent(html string, opts goja.Value) error {
 	f.log.Debugf("Frame:SetContent", "fid:%s furl:%q", f.ID(), f.URL())
 
 	parsedOpts := NewFrameSetContentOptions(f.defaultTimeout())
 	if err := parsedOpts.Parse(f.ctx, opts); err != nil {
-		k6ext.Panic(f.ctx, "parsing set content options: %w", err)
+		return fmt.Errorf("parsing set content options: %w", err)
 	}
 
 	js := `(html) => {
@@ -1431,10 +1431,12 @@ func (f *Frame) SetContent(html string, opts goja.Value) {
 	}
 	rt := f.vu.Runtime()
 	if _, err := f.evaluate(f.ctx, utilityWorld, eopts, rt.ToValue(js), rt.ToValue(html)); err != nil {
-		k6ext.Panic(f.ctx, "setting content: %w", err)
+		return fmt.Errorf("setting content: %w", err)
 	}
 
 	applySlowMo(f.ctx)
+
+	return nil
 }
 
 // SetInputFiles is not implemented.
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -275,6 +276,37 @@ func (fs *FrameSession) onEventBindingCalled(event *cdpruntime.EventBindingCalle
 		"sid:%v tid:%v name:%s payload:%s",
 		fs.session.ID(), fs.targetID, event.Name, event.Payload)
 
+	var payload struct {
+		Type   string
+		Origin string
+	}
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		fs.logger.Errorf("FrameSession:onEventBindingCalled", "failed to parse metric: %v", err)
+		return
+	}
+	switch payload.Type {
+	case storageStateRestoredPayloadType:
+		if fs.page != nil && fs.page.browserCtx != nil {
+			fs.page.browserCtx.localStorageRestored(payload.Origin)
+		}
+		return
+	case navigationTimingPayloadType:
+		if err := fs.parseAndEmitNavigationTimingMetric(event.Payload); err != nil {
+			fs.logger.Errorf("FrameSession:onEventBindingCalled", "failed to emit navigation timing metric: %v", err)
+		}
+		return
+	case userTimingPayloadType:
+		if err := fs.parseAndEmitUserTimingMetric(event.Payload); err != nil {
+			fs.logger.Errorf("FrameSession:onEventBindingCalled", "failed to emit user timing metric: %v", err)
+		}
+		return
+	case longTaskPayloadType, totalBlockingTimePayloadType:
+		if err := fs.parseAndEmitLongTaskMetric(payload.Type, event.Payload); err != nil {
+			fs.logger.Errorf("FrameSession:onEventBindingCalled", "failed to emit long task metric: %v", err)
+		}
+		return
+	}
+
 	err := fs.parseAndEmitWebVitalMetric(event.Payload)
 	if err != nil {
 		fs.logger.Errorf("FrameSession:onEventBindingCalled", "failed to emit web vital metric: %v", err)
@@ -315,10 +347,7 @@ func (fs *FrameSession) parseAndEmitWebVitalMetric(object string) error {
 	}
 
 	state := fs.vu.State()
-	tags := state.Tags.GetCurrentValues().Tags
-	if state.Options.SystemTags.Has(k6metrics.TagURL) {
-		tags = tags.With("url", wv.URL)
-	}
+	tags := fs.pageMetricTags(wv.URL)
 
 	now := time.Now()
 	k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.ConnectedSamples{
@@ -339,6 +368,137 @@ func (fs *FrameSession) parseAndEmitWebVitalMetric(object string) error {
 	return nil
 }
 
+// parseAndEmitNavigationTimingMetric emits a navigation timing of a page,
+// such as the end of its load event, in milliseconds since the start
+// of its navigation.
+func (fs *FrameSession) parseAndEmitNavigationTimingMetric(object string) error {
+	fs.logger.Debugf("FrameSession:parseAndEmitNavigationTimingMetric", "object:%s", object)
+
+	nt := struct {
+		Name           string
+		Value          json.Number
+		NavigationType string
+		URL            string
+	}{}
+	if err := json.Unmarshal([]byte(object), &nt); err != nil {
+		return fmt.Errorf("json couldn't be parsed: %w", err)
+	}
+
+	metric, ok := fs.k6Metrics.NavigationTimings[nt.Name]
+	if !ok {
+		return fmt.Errorf("metric not registered %q", nt.Name)
+	}
+	value, err := nt.Value.Float64()
+	if err != nil {
+		return fmt.Errorf("value couldn't be parsed %q", nt.Value)
+	}
+
+	state := fs.vu.State()
+	tags := fs.pageMetricTags(nt.URL).With("navigation_type", nt.NavigationType)
+	k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.Sample{
+		TimeSeries: k6metrics.TimeSeries{Metric: metric, Tags: tags},
+		Value:      value,
+		Time:       time.Now(),
+	})
+
+	return nil
+}
+
+// parseAndEmitUserTimingMetric emits the duration of
+// a measure of the page, tagged with the measure name.
+func (fs *FrameSession) parseAndEmitUserTimingMetric(object string) error {
+	fs.logger.Debugf("FrameSession:parseAndEmitUserTimingMetric", "object:%s", object)
+
+	ut := struct {
+		Name  string
+		Value json.Number
+		URL   string
+	}{}
+	if err := json.Unmarshal([]byte(object), &ut); err != nil {
+		return fmt.Errorf("json couldn't be parsed: %w", err)
+	}
+	value, err := ut.Value.Float64()
+	if err != nil {
+		return fmt.Errorf("value couldn't be parsed %q", ut.Value)
+	}
+
+	state := fs.vu.State()
+	tags := fs.pageMetricTags(ut.URL).With("measure", ut.Name)
+	k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.Sample{
+		TimeSeries: k6metrics.TimeSeries{Metric: fs.k6Metrics.UserTiming, Tags: tags},
+		Value:      value,
+		Time:       time.Now(),
+	})
+
+	return nil
+}
+
+// parseAndEmitLongTaskMetric emits the metrics of a long task of the page,
+// or the total blocking time of the long tasks of a navigation of the page.
+func (fs *FrameSession) parseAndEmitLongTaskMetric(typ, object string) error {
+	fs.logger.Debugf("FrameSession:parseAndEmitLongTaskMetric", "type:%s object:%s", typ, object)
+
+	lt := struct {
+		Value          json.Number
+		NavigationType string
+		URL            string
+	}{}
+	if err := json.Unmarshal([]byte(object), &lt); err != nil {
+		return fmt.Errorf("json couldn't be parsed: %w", err)
+	}
+	value, err := lt.Value.Float64()
+	if err != nil {
+		return fmt.Errorf("value couldn't be parsed %q", lt.Value)
+	}
+
+	var (
+		state = fs.vu.State()
+		tags  = fs.pageMetricTags(lt.URL)
+		now   = time.Now()
+	)
+	if typ == totalBlockingTimePayloadType {
+		k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.Sample{
+			TimeSeries: k6metrics.TimeSeries{
+				Metric: fs.k6Metrics.TotalBlockingTime,
+				Tags:   tags.With("navigation_type", lt.NavigationType),
+			},
+			Value: value,
+			Time:  now,
+		})
+		return nil
+	}
+	k6metrics.PushIfNotDone(fs.ctx, state.Samples, k6metrics.ConnectedSamples{
+		Samples: []k6metrics.Sample{
+			{
+				TimeSeries: k6metrics.TimeSeries{Metric: fs.k6Metrics.LongTasks, Tags: tags},
+				Value:      1,
+				Time:       now,
+			},
+			{
+				TimeSeries: k6metrics.TimeSeries{Metric: fs.k6Metrics.LongTaskDuration, Tags: tags},
+				Value:      value,
+				Time:       now,
+			},
+		},
+	})
+
+	return nil
+}
+
+// pageMetricTags returns the tags of the metrics measured in the page.
+func (fs *FrameSession) pageMetricTags(url string) *k6metrics.TagSet {
+	state := fs.vu.State()
+	tags := state.Tags.GetCurrentValues().Tags
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags = tags.With("url", url)
+	}
+	if rate := fs.page.getCPUThrottlingRate(); rate > 0 {
+		tags = tags.With("cpu_throttling_rate", strconv.FormatFloat(rate, 'f', -1, 64))
+	}
+
+	return tags
+}
+
 func (fs *FrameSession) onEventJavascriptDialogOpening(event *cdppage.EventJavascriptDialogOpening) {
 	fs.logger.Debugf("FrameSession:onEventJavascriptDialogOpening",
 		"sid:%v tid:%v url:%v dialogType:%s",
@@ -444,7 +604,6 @@ func (fs *FrameSession) initOptions() error {
 	var (
 		opts       = fs.manager.page.browserCtx.opts
 		optActions = []Action{}
-		state      = fs.vu.State()
 	)
 
 	if fs.isMainFrame() {
@@ -465,6 +624,9 @@ func (fs *FrameSession) initOptions() error {
 	if opts.HasTouch {
 		optActions = append(optActions, emulation.SetTouchEmulationEnabled(true))
 	}
+	if opts.ServiceWorkers == ServiceWorkersBlock {
+		optActions = append(optActions, network.SetBypassServiceWorker(true))
+	}
 	if !opts.JavaScriptEnabled {
 		optActions = append(optActions, emulation.SetScriptExecutionDisabled(true))
 	}
@@ -486,17 +648,17 @@ func (fs *FrameSession) initOptions() error {
 	}
 	fs.updateExtraHTTPHeaders(true)
 
-	var reqIntercept bool
-	if state.Options.BlockedHostnames.Trie != nil ||
-		len(state.Options.BlacklistIPs) > 0 {
-		reqIntercept = true
-	}
-	if err := fs.updateRequestInterception(reqIntercept); err != nil {
+	if err := fs.updateRequestInterception(); err != nil {
 		return err
 	}
 
 	fs.updateOffline(true)
+	fs.updateNetworkConditions(true)
+	if err := fs.updateCPUThrottlingRate(true); err != nil {
+		return err
+	}
 	fs.updateHTTPCredentials(true)
+	fs.updateProxyCredentials()
 	if err := fs.updateEmulateMedia(true); err != nil {
 		return err
 	}
@@ -918,7 +1080,7 @@ func (fs *FrameSession) attachIFrameToTarget(ti *target.Info, sid target.Session
 
 // attachWorkerToTarget attaches a Worker target to a given session.
 func (fs *FrameSession) attachWorkerToTarget(ti *target.Info, sid target.SessionID) error {
-	w, err := NewWorker(fs.ctx, fs.page.browserCtx.getSession(sid), ti.TargetID, ti.URL)
+	w, err := NewWorker(fs.ctx, fs.page.browserCtx.getSession(sid), ti.TargetID, ti.URL, fs.logger)
 	if err != nil {
 		return fmt.Errorf("attaching worker target ID %v to session ID %v: %w",
 			ti.TargetID, sid, err)
@@ -1019,6 +1181,14 @@ func (fs *FrameSession) updateHTTPCredentials(initial bool) {
 	}
 }
 
+func (fs *FrameSession) updateProxyCredentials() {
+	fs.logger.Debugf("NewFrameSession:updateProxyCredentials", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
+
+	if credentials := fs.page.browserCtx.opts.Proxy.credentials(); credentials != nil {
+		fs.networkManager.AuthenticateProxy(credentials)
+	}
+}
+
 func (fs *FrameSession) updateOffline(initial bool) {
 	fs.logger.Debugf("NewFrameSession:updateOffline", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
 
@@ -1028,13 +1198,50 @@ func (fs *FrameSession) updateOffline(initial bool) {
 	}
 }
 
-func (fs *FrameSession) updateRequestInterception(enable bool) error {
+func (fs *FrameSession) updateNetworkConditions(initial bool) {
+	fs.logger.Debugf("NewFrameSession:updateNetworkConditions", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
+
+	conditions := fs.page.networkConditions
+	if !initial || conditions != nil {
+		fs.networkManager.SetNetworkConditions(conditions)
+	}
+}
+
+func (fs *FrameSession) updateCPUThrottlingRate(initial bool) error {
+	fs.logger.Debugf("NewFrameSession:updateCPUThrottlingRate", "sid:%v tid:%v", fs.session.ID(), fs.targetID)
+
+	rate := fs.page.getCPUThrottlingRate()
+	if initial && rate == 0 {
+		return nil
+	}
+	if rate < 1 {
+		rate = 1
+	}
+
+	action := emulation.SetCPUThrottlingRate(rate)
+	if err := action.Do(cdp.WithExecutor(fs.ctx, fs.session)); err != nil {
+		return fmt.Errorf("setting CPU throttling rate: %w", err)
+	}
+
+	return nil
+}
+
+func (fs *FrameSession) updateRequestInterception() error {
+	state := fs.vu.State()
+	enable := state.Options.BlockedHostnames.Trie != nil ||
+		len(state.Options.BlacklistIPs) > 0 ||
+		fs.page.browserCtx.opts.HttpCredentials != nil ||
+		fs.page.browserCtx.opts.Proxy.credentials() != nil ||
+		fs.page.browserCtx.opts.BlockedRequests != nil ||
+		fs.page.hasRoutes() ||
+		fs.page.browserCtx.hasRoutes()
+
 	fs.logger.Debugf("NewFrameSession:updateRequestInterception",
 		"sid:%v tid:%v on:%v",
 		fs.session.ID(),
 		fs.targetID, enable)
 
-	return fs.networkManager.setRequestInterception(enable || fs.page.hasRoutes())
+	return fs.networkManager.setRequestInterception(enable)
 }
 
 func (fs *FrameSession) updateViewport() error {
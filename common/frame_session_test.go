@@ -0,0 +1,111 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+func newTestFrameSession(t *testing.T) (*FrameSession, *k6test.VU) {
+	t.Helper()
+
+	vu := k6test.NewVU(t)
+	vu.MoveToVUContext()
+	fs := &FrameSession{
+		ctx:       vu.Context(),
+		session:   &fakeSession{session: &Session{id: "1234"}},
+		page:      &Page{},
+		k6Metrics: k6ext.RegisterCustomMetrics(k6metrics.NewRegistry()),
+		vu:        vu,
+		logger:    log.NewNullLogger(),
+	}
+
+	return fs, vu
+}
+
+func TestFrameSessionNavigationTimingMetric(t *testing.T) {
+	t.Parallel()
+
+	fs, vu := newTestFrameSession(t)
+
+	fs.onEventBindingCalled(&runtime.EventBindingCalled{
+		Name: webVitalBinding,
+		Payload: `{"type":"navigationTiming","name":"load","value":123.5,` +
+			`"navigationType":"reload","url":"http://host.com/"}`,
+	})
+	// unknown navigation timings are not emitted.
+	fs.onEventBindingCalled(&runtime.EventBindingCalled{
+		Name:    webVitalBinding,
+		Payload: `{"type":"navigationTiming","name":"unknown","value":1}`,
+	})
+
+	var samples []k6metrics.Sample
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		samples = append(samples, s)
+	})
+	require.Len(t, samples, 1)
+	assert.Equal(t, "browser_load", samples[0].Metric.Name)
+	assert.Equal(t, 123.5, samples[0].Value)
+	url, _ := samples[0].Tags.Get("url")
+	assert.Equal(t, "http://host.com/", url)
+	typ, _ := samples[0].Tags.Get("navigation_type")
+	assert.Equal(t, "reload", typ)
+}
+
+func TestFrameSessionUserTimingMetric(t *testing.T) {
+	t.Parallel()
+
+	fs, vu := newTestFrameSession(t)
+
+	fs.onEventBindingCalled(&runtime.EventBindingCalled{
+		Name:    webVitalBinding,
+		Payload: `{"type":"userTiming","name":"checkout","value":42.25,"url":"http://host.com/"}`,
+	})
+
+	var samples []k6metrics.Sample
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		samples = append(samples, s)
+	})
+	require.Len(t, samples, 1)
+	assert.Equal(t, "browser_user_timing", samples[0].Metric.Name)
+	assert.Equal(t, 42.25, samples[0].Value)
+	measure, _ := samples[0].Tags.Get("measure")
+	assert.Equal(t, "checkout", measure)
+}
+
+func TestFrameSessionLongTaskMetrics(t *testing.T) {
+	t.Parallel()
+
+	fs, vu := newTestFrameSession(t)
+
+	fs.onEventBindingCalled(&runtime.EventBindingCalled{
+		Name:    webVitalBinding,
+		Payload: `{"type":"longTask","value":120,"url":"http://host.com/"}`,
+	})
+	fs.onEventBindingCalled(&runtime.EventBindingCalled{
+		Name:    webVitalBinding,
+		Payload: `{"type":"totalBlockingTime","value":70,"navigationType":"navigate","url":"http://host.com/"}`,
+	})
+
+	values := make(map[string]float64)
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		values[s.Metric.Name] = s.Value
+		if s.Metric.Name == "browser_tbt" {
+			typ, _ := s.Tags.Get("navigation_type")
+			assert.Equal(t, "navigate", typ)
+		}
+	})
+	assert.Equal(t, map[string]float64{
+		"browser_long_tasks":         1,
+		"browser_long_task_duration": 120,
+		"browser_tbt":                70,
+	}, values)
+}
@@ -0,0 +1,569 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	k6consts "go.k6.io/k6/lib/consts"
+
+	"github.com/dop251/goja"
+)
+
+// HAR content policies.
+const (
+	// HARContentEmbed embeds the response bodies in the HAR file.
+	HARContentEmbed = "embed"
+	// HARContentOmit omits the response bodies from the HAR file.
+	HARContentOmit = "omit"
+)
+
+const (
+	// harVersion is the HAR format version of the recorded files.
+	harVersion = "1.2"
+	// harTimeFormat is a fixed width ISO 8601 format, so that
+	// the formatted times can be compared as strings.
+	harTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// RecordHAROptions are the options for recording the network
+// activity of a browser context into a HAR file.
+type RecordHAROptions struct {
+	Path    string `js:"path"`
+	Content string `js:"content"`
+
+	urlFilter urlMatcher
+}
+
+// NewRecordHAROptions returns a new RecordHAROptions.
+func NewRecordHAROptions() *RecordHAROptions {
+	return &RecordHAROptions{
+		Content: HARContentEmbed,
+	}
+}
+
+// Parse parses the HAR recording options.
+func (o *RecordHAROptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return errors.New("recordHar options must be an object")
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "path":
+			o.Path = obj.Get(k).String()
+		case "content":
+			switch c := obj.Get(k).String(); c {
+			case HARContentEmbed, HARContentOmit:
+				o.Content = c
+			default:
+				return fmt.Errorf("recordHar content must be %q or %q, got %q", HARContentEmbed, HARContentOmit, c)
+			}
+		case "urlFilter":
+			m, err := newURLMatcher(rt, obj.Get(k))
+			if err != nil {
+				return fmt.Errorf("parsing recordHar urlFilter: %w", err)
+			}
+			o.urlFilter = m
+		}
+	}
+	if o.Path == "" {
+		return errors.New("recordHar path must be set")
+	}
+
+	return nil
+}
+
+type harLog struct {
+	Log harLogContent `json:"log"`
+}
+
+type harLogContent struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Pages   []*harPage  `json:"pages"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Comment string `json:"comment,omitempty"`
+}
+
+type harPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+}
+
+type harPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+type harEntry struct {
+	PageRef         string      `json:"pageref,omitempty"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+	FailureText     string      `json:"_failureText,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int64          `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harRecorder records the requests of a browser context as HAR entries.
+type harRecorder struct {
+	opts *RecordHAROptions
+
+	mu       sync.Mutex
+	pages    []*harPage
+	pageRefs map[*Page]string
+	entries  []*harEntry
+}
+
+func newHARRecorder(opts *RecordHAROptions) *harRecorder {
+	return &harRecorder{
+		opts:     opts,
+		pageRefs: make(map[*Page]string),
+	}
+}
+
+// record adds a HAR entry for a finished, failed or redirected request.
+// The end time is when the browser finished receiving the response or
+// when the request failed.
+func (h *harRecorder) record(page *Page, req *Request, end time.Time) {
+	if h.opts.urlFilter != nil && !h.opts.urlFilter(req.URL()) {
+		return
+	}
+	entry := newHAREntry(req, end, h.opts.Content == HARContentEmbed)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if page != nil {
+		ref, ok := h.pageRefs[page]
+		if !ok {
+			ref = fmt.Sprintf("page_%d", len(h.pages)+1)
+			h.pageRefs[page] = ref
+			h.pages = append(h.pages, &harPage{
+				StartedDateTime: entry.StartedDateTime,
+				ID:              ref,
+				Title:           req.URL(),
+				PageTimings:     harPageTimings{OnContentLoad: -1, OnLoad: -1},
+			})
+		}
+		entry.PageRef = ref
+	}
+	h.entries = append(h.entries, entry)
+}
+
+// write writes the recorded entries to the HAR file.
+func (h *harRecorder) write() error {
+	h.mu.Lock()
+	har := harLog{
+		Log: harLogContent{
+			Version: harVersion,
+			Creator: harCreator{
+				Name:    "k6",
+				Version: k6consts.Version,
+				Comment: "xk6-browser",
+			},
+			Pages:   append([]*harPage{}, h.pages...),
+			Entries: append([]*harEntry{}, h.entries...),
+		},
+	}
+	h.mu.Unlock()
+
+	sort.SliceStable(har.Log.Entries, func(i, j int) bool {
+		return har.Log.Entries[i].StartedDateTime < har.Log.Entries[j].StartedDateTime
+	})
+	buf, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling HAR: %w", err)
+	}
+	if dir := filepath.Dir(h.opts.Path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating HAR directory %q: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(h.opts.Path, buf, 0o644); err != nil {
+		return fmt.Errorf("writing HAR file %q: %w", h.opts.Path, err)
+	}
+
+	return nil
+}
+
+func newHAREntry(req *Request, end time.Time, embedContent bool) *harEntry {
+	entry := &harEntry{
+		StartedDateTime: req.wallTime.Format(harTimeFormat),
+		Time:            toMillis(end.Sub(req.wallTime)),
+		Request: harRequest{
+			Method:      req.method,
+			URL:         req.URL(),
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     toHARNameValues(req.headers),
+			QueryString: toHARNameValues(req.url.Query()),
+			HeadersSize: req.headersSize(),
+			BodySize:    int64(len(req.postData)),
+		},
+		Response: harResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValue{},
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: "x-unknown"},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings:      harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: -1},
+		ResourceType: req.resourceType,
+		FailureText:  req.errorText,
+	}
+	if req.postData != "" {
+		entry.Request.PostData = &harPostData{
+			MimeType: headerValue(req.headers, "content-type"),
+			Text:     req.postData,
+		}
+	}
+
+	req.responseMu.RLock()
+	resp := req.response
+	req.responseMu.RUnlock()
+	if resp == nil {
+		entry.Timings.Receive = entry.Time
+		return entry
+	}
+
+	if v := toHARHTTPVersion(resp.protocol); v != "" {
+		entry.Request.HTTPVersion = v
+		entry.Response.HTTPVersion = v
+	}
+	entry.Response.Status = resp.status
+	entry.Response.StatusText = resp.statusText
+	entry.Response.Headers = toHARNameValues(resp.headers)
+	entry.Response.RedirectURL = headerValue(resp.headers, "location")
+	entry.Response.HeadersSize = resp.headersSize()
+	if resp.remoteAddress != nil {
+		entry.ServerIPAddress = resp.remoteAddress.IPAddress
+	}
+	if mt := headerValue(resp.headers, "content-type"); mt != "" {
+		entry.Response.Content.MimeType = mt
+	}
+
+	resp.bodyMu.RLock()
+	body := resp.body
+	resp.bodyMu.RUnlock()
+	entry.Response.BodySize = int64(len(body))
+	entry.Response.Content.Size = int64(len(body))
+	if embedContent && len(body) > 0 {
+		if utf8.Valid(body) {
+			entry.Response.Content.Text = string(body)
+		} else {
+			entry.Response.Content.Text = base64.StdEncoding.EncodeToString(body)
+			entry.Response.Content.Encoding = "base64"
+		}
+	}
+
+	entry.Timings = toHARTimings(resp, entry.Time)
+
+	return entry
+}
+
+// toHARTimings converts the resource timing of the response to HAR timings.
+// Timings that are not applicable to the request are set to -1.
+func toHARTimings(resp *Response, total float64) harTimings {
+	t := harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: -1, Receive: total}
+	rt := resp.timing
+	if rt == nil {
+		return t
+	}
+
+	span := func(start, end float64) float64 {
+		if start < 0 || end < start {
+			return -1
+		}
+		return end - start
+	}
+	for _, start := range []float64{rt.DNSStart, rt.ConnectStart, rt.SendStart} {
+		if start >= 0 {
+			t.Blocked = start
+			break
+		}
+	}
+	t.DNS = span(rt.DNSStart, rt.DNSEnd)
+	t.Connect = span(rt.ConnectStart, rt.ConnectEnd)
+	t.SSL = span(rt.SslStart, rt.SslEnd)
+	t.Send = span(rt.SendStart, rt.SendEnd)
+	if t.Send < 0 {
+		t.Send = 0
+	}
+	t.Wait = span(rt.SendEnd, rt.ReceiveHeadersEnd)
+
+	t.Receive = total
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait} {
+		if v > 0 {
+			t.Receive -= v
+		}
+	}
+	if t.Receive < 0 {
+		t.Receive = 0
+	}
+
+	return t
+}
+
+func toHARNameValues(m map[string][]string) []harNameValue {
+	names := make([]string, 0, len(m))
+	for n := range m {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	nvs := make([]harNameValue, 0, len(m))
+	for _, n := range names {
+		for _, v := range m[n] {
+			nvs = append(nvs, harNameValue{Name: n, Value: v})
+		}
+	}
+
+	return nvs
+}
+
+func toHARHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "":
+		return ""
+	case "h2":
+		return "HTTP/2.0"
+	case "h3":
+		return "HTTP/3.0"
+	default:
+		return strings.ToUpper(protocol)
+	}
+}
+
+// headerValue returns the first value of a header
+// with a case-insensitive name lookup.
+func headerValue(headers map[string][]string, name string) string {
+	for n, v := range headers {
+		if strings.EqualFold(n, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// find returns the first recorded entry that matches
+// the method, the URL and the POST body of a request.
+func (h *harRecorder) find(method, url, postData string) *harEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, e := range h.entries {
+		if e.Request.Method != method || e.Request.URL != url {
+			continue
+		}
+		var pd string
+		if e.Request.PostData != nil {
+			pd = e.Request.PostData.Text
+		}
+		if pd == postData {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// harRouter answers requests with the entries of a HAR file.
+// In update mode, it records the requests that are missing
+// from the HAR file and writes them back to it.
+type harRouter struct {
+	opts    *RouteFromHAROptions
+	matcher urlMatcher
+	har     *harRecorder
+}
+
+// newHARRouteHandler returns a route handler that answers
+// the requests from the HAR file at path.
+func newHARRouteHandler(
+	ctx context.Context, path string, opts goja.Value,
+) (*routeHandler, *harRouter, error) {
+	ropts := NewRouteFromHAROptions()
+	if err := ropts.Parse(ctx, opts); err != nil {
+		return nil, nil, fmt.Errorf("parsing routeFromHAR options: %w", err)
+	}
+	matcher := func(string) bool { return true }
+	if gojaValueExists(ropts.URL) {
+		m, err := newURLMatcher(k6ext.Runtime(ctx), ropts.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		matcher = m
+	}
+	router, err := newHARRouter(path, ropts, matcher)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &routeHandler{
+		url:     ropts.URL,
+		matcher: matcher,
+		handle:  router.handle,
+	}, router, nil
+}
+
+func newHARRouter(path string, opts *RouteFromHAROptions, matcher urlMatcher) (*harRouter, error) {
+	rec := newHARRecorder(&RecordHAROptions{Path: path, Content: HARContentEmbed})
+
+	buf, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && opts.Update:
+		// the HAR file is created when the browser context is closed.
+	case err != nil:
+		return nil, fmt.Errorf("reading HAR file %q: %w", path, err)
+	default:
+		var har harLog
+		if err := json.Unmarshal(buf, &har); err != nil {
+			return nil, fmt.Errorf("parsing HAR file %q: %w", path, err)
+		}
+		rec.pages = har.Log.Pages
+		rec.entries = har.Log.Entries
+	}
+
+	return &harRouter{
+		opts:    opts,
+		matcher: matcher,
+		har:     rec,
+	}, nil
+}
+
+// handle fulfills the route with the matching HAR entry. If there is no
+// matching entry, the route is aborted unless the router falls back.
+func (r *harRouter) handle(route *Route) error {
+	req := route.request
+	e := r.har.find(req.method, req.URL(), req.postData)
+	if e == nil || e.Response.Status <= 0 {
+		if r.opts.Update || r.opts.NotFound == HARNotFoundFallback {
+			return nil
+		}
+		return route.abort("failed")
+	}
+
+	body, err := e.Response.Content.bytes()
+	if err != nil {
+		return fmt.Errorf("decoding HAR content of %s %s: %w", req.method, req.URL(), err)
+	}
+	headers := make(map[string]string)
+	for _, h := range e.Response.Headers {
+		n := strings.ToLower(h.Name)
+		// the body is stored decoded and the content length is
+		// computed from it, and pseudo headers can't be set.
+		if n == "content-encoding" || n == "content-length" || strings.HasPrefix(n, ":") {
+			continue
+		}
+		if v, ok := headers[n]; ok {
+			headers[n] = v + ", " + h.Value
+			continue
+		}
+		headers[n] = h.Value
+	}
+
+	return route.fulfill(&RouteFulfillOptions{
+		Status:  e.Response.Status,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+// record records the request if the router is in update mode
+// and the HAR file doesn't have a matching entry for it yet.
+func (r *harRouter) record(page *Page, req *Request, end time.Time) {
+	if !r.opts.Update || !r.matcher(req.URL()) {
+		return
+	}
+	if r.har.find(req.method, req.URL(), req.postData) != nil {
+		return
+	}
+	r.har.record(page, req, end)
+}
+
+// bytes returns the decoded content.
+func (c harContent) bytes() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text) //nolint:wrapcheck
+	}
+	return []byte(c.Text), nil
+}
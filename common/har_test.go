@@ -0,0 +1,272 @@
+package common
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordHAROptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := NewRecordHAROptions()
+		err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+			"path":      "out.har",
+			"content":   "omit",
+			"urlFilter": "**/api/**",
+		}))
+		require.NoError(t, err)
+		assert.Equal(t, "out.har", opts.Path)
+		assert.Equal(t, HARContentOmit, opts.Content)
+		require.NotNil(t, opts.urlFilter)
+		assert.True(t, opts.urlFilter("https://test/api/v1/users"))
+		assert.False(t, opts.urlFilter("https://test/static/app.js"))
+	})
+	t.Run("err_content", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := NewRecordHAROptions()
+		err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+			"path":    "out.har",
+			"content": "attach",
+		}))
+		assert.ErrorContains(t, err, `recordHar content must be "embed" or "omit"`)
+	})
+	t.Run("err_path", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		opts := NewRecordHAROptions()
+		err := opts.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+			"content": "embed",
+		}))
+		assert.EqualError(t, err, "recordHar path must be set")
+	})
+}
+
+func TestHARRecorder(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	vu.MoveToVUContext()
+	ts := cdp.MonotonicTime(time.Now())
+	wt := cdp.TimeSinceEpoch(time.Now())
+	req, err := NewRequest(vu.Context(), NewRequestParams{
+		event: &network.EventRequestWillBeSent{
+			RequestID: network.RequestID("1234"),
+			Request: &network.Request{
+				URL:    "https://test/api?q=1",
+				Method: "POST",
+				Headers: network.Headers(map[string]any{
+					"Content-Type": "application/json",
+				}),
+				PostData: `{"a":1}`,
+			},
+			Timestamp: &ts,
+			WallTime:  &wt,
+		},
+	})
+	require.NoError(t, err)
+
+	resp := NewHTTPResponse(vu.Context(), req, &network.Response{
+		URL:             "https://test/api?q=1",
+		Status:          201,
+		StatusText:      "Created",
+		Protocol:        "h2",
+		RemoteIPAddress: "127.0.0.1",
+		Headers: network.Headers(map[string]any{
+			"Content-Type": "text/plain",
+		}),
+		Timing: &network.ResourceTiming{
+			DNSStart:          1,
+			DNSEnd:            2,
+			ConnectStart:      2,
+			ConnectEnd:        5,
+			SslStart:          3,
+			SslEnd:            5,
+			SendStart:         5,
+			SendEnd:           6,
+			ReceiveHeadersEnd: 10,
+		},
+	}, &ts)
+	resp.body = []byte("created")
+	req.response = resp
+
+	path := filepath.Join(t.TempDir(), "out.har")
+	rec := newHARRecorder(&RecordHAROptions{Path: path, Content: HARContentEmbed})
+	page := &Page{}
+	rec.record(page, req, wt.Time().Add(20*time.Millisecond))
+	require.NoError(t, rec.write())
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var har harLog
+	require.NoError(t, json.Unmarshal(buf, &har))
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Pages, 1)
+	require.Len(t, har.Log.Entries, 1)
+
+	e := har.Log.Entries[0]
+	assert.Equal(t, har.Log.Pages[0].ID, e.PageRef)
+	assert.Equal(t, 20.0, e.Time)
+	assert.Equal(t, "POST", e.Request.Method)
+	assert.Equal(t, "HTTP/2.0", e.Request.HTTPVersion)
+	assert.Equal(t, []harNameValue{{Name: "q", Value: "1"}}, e.Request.QueryString)
+	require.NotNil(t, e.Request.PostData)
+	assert.Equal(t, "application/json", e.Request.PostData.MimeType)
+	assert.Equal(t, `{"a":1}`, e.Request.PostData.Text)
+	assert.Equal(t, int64(201), e.Response.Status)
+	assert.Equal(t, "text/plain", e.Response.Content.MimeType)
+	assert.Equal(t, "created", e.Response.Content.Text)
+	assert.Equal(t, int64(7), e.Response.Content.Size)
+	assert.Equal(t, "127.0.0.1", e.ServerIPAddress)
+	assert.Equal(t, harTimings{
+		Blocked: 1, DNS: 1, Connect: 3, SSL: 2, Send: 1, Wait: 4, Receive: 10,
+	}, e.Timings)
+}
+
+func TestHARRouter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "in.har")
+	har := harLog{
+		Log: harLogContent{
+			Version: harVersion,
+			Entries: []*harEntry{
+				{
+					Request: harRequest{Method: "GET", URL: "https://test/api"},
+					Response: harResponse{
+						Status: 200,
+						Headers: []harNameValue{
+							{Name: "Content-Type", Value: "application/json"},
+							{Name: "Content-Encoding", Value: "gzip"},
+						},
+						Content: harContent{Text: "eyJhIjoxfQ==", Encoding: "base64"},
+					},
+				},
+				{
+					Request: harRequest{
+						Method:   "POST",
+						URL:      "https://test/api",
+						PostData: &harPostData{Text: "a=1"},
+					},
+					Response: harResponse{Status: 201},
+				},
+			},
+		},
+	}
+	buf, err := json.Marshal(har)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, buf, 0o600))
+
+	newRoute := func(t *testing.T, method, url, postData string) (*Route, *fakeSession) {
+		t.Helper()
+
+		vu := k6test.NewVU(t)
+		ts := cdp.MonotonicTime(time.Now())
+		wt := cdp.TimeSinceEpoch(time.Now())
+		req, err := NewRequest(vu.Context(), NewRequestParams{
+			event: &network.EventRequestWillBeSent{
+				RequestID: network.RequestID("1234"),
+				Request: &network.Request{
+					URL:      url,
+					Method:   method,
+					PostData: postData,
+				},
+				Timestamp: &ts,
+				WallTime:  &wt,
+			},
+		})
+		require.NoError(t, err)
+		session := &fakeSession{session: &Session{id: "1234"}}
+
+		return NewRoute(vu.Context(), session, req, "1234", log.NewNullLogger()), session
+	}
+
+	testCases := []struct {
+		name, method, url, postData, notFound string
+		expCDPCalls                           []string
+	}{
+		{
+			name:        "get",
+			method:      "GET",
+			url:         "https://test/api",
+			notFound:    HARNotFoundAbort,
+			expCDPCalls: []string{"Fetch.fulfillRequest"},
+		},
+		{
+			name:        "post",
+			method:      "POST",
+			url:         "https://test/api",
+			postData:    "a=1",
+			notFound:    HARNotFoundAbort,
+			expCDPCalls: []string{"Fetch.fulfillRequest"},
+		},
+		{
+			name:        "post_body_mismatch_abort",
+			method:      "POST",
+			url:         "https://test/api",
+			postData:    "a=2",
+			notFound:    HARNotFoundAbort,
+			expCDPCalls: []string{"Fetch.failRequest"},
+		},
+		{
+			name:        "not_found_fallback",
+			method:      "GET",
+			url:         "https://test/other",
+			notFound:    HARNotFoundFallback,
+			expCDPCalls: nil,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := &RouteFromHAROptions{NotFound: tc.notFound}
+			router, err := newHARRouter(path, opts, func(string) bool { return true })
+			require.NoError(t, err)
+
+			route, session := newRoute(t, tc.method, tc.url, tc.postData)
+			require.NoError(t, router.handle(route))
+			assert.Equal(t, tc.expCDPCalls, session.cdpCalls)
+		})
+	}
+
+	t.Run("update", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "missing.har")
+		opts := &RouteFromHAROptions{NotFound: HARNotFoundAbort, Update: true}
+		router, err := newHARRouter(path, opts, func(string) bool { return true })
+		require.NoError(t, err)
+
+		route, session := newRoute(t, "GET", "https://test/api", "")
+		require.NoError(t, router.handle(route))
+		assert.Empty(t, session.cdpCalls)
+
+		router.record(nil, route.request, time.Now())
+		router.record(nil, route.request, time.Now())
+		require.NoError(t, router.har.write())
+
+		updated, err := newHARRouter(path, &RouteFromHAROptions{}, func(string) bool { return true })
+		require.NoError(t, err)
+		assert.Len(t, updated.har.entries, 1)
+	})
+}
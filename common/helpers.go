@@ -11,6 +11,7 @@ import (
 	"github.com/dop251/goja"
 
 	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
 )
 
 func convertBaseJSHandleTypes(ctx context.Context, execCtx *ExecutionContext, objHandle *BaseJSHandle) (*cdpruntime.CallArgument, error) {
@@ -198,6 +199,47 @@ func createWaitForEventPredicateHandler(
 	return ch, evCancelFn
 }
 
+// handleEvents calls the handler on the event loop with the data of each
+// event of the given types that the emitter emits, until ctx or done is done
+// or the iteration ends. The handler is called while the VU waits on a
+// promise, and it doesn't keep the event loop alive. It must be called on
+// the event loop. Handler errors are logged since there is no caller to
+// return them to.
+func handleEvents(
+	ctx context.Context, done <-chan struct{}, emitter EventEmitter, events []string,
+	handler func(data any) error, logger *log.Logger,
+) {
+	var (
+		evCancelCtx, evCancelFn = context.WithCancel(ctx)
+		chEvHandler             = make(chan Event)
+		queue                   = k6ext.NewPassiveTaskQueue(k6ext.GetVU(ctx))
+	)
+	go func() {
+		defer queue.Close()
+		defer evCancelFn()
+
+		for {
+			select {
+			case <-evCancelCtx.Done():
+				return
+			case <-done:
+				return
+			case <-queue.Done():
+				return
+			case ev := <-chEvHandler:
+				queue.Queue(func() error {
+					if err := handler(ev.data); err != nil {
+						logger.Errorf("handleEvents", "handling %q event: %v", ev.typ, err)
+					}
+					return nil
+				})
+			}
+		}
+	}()
+
+	emitter.on(evCancelCtx, events, chEvHandler)
+}
+
 // panicOrSlowMo panics if err is not nil, otherwise applies slow motion.
 func panicOrSlowMo(ctx context.Context, err error) {
 	if err != nil {
@@ -0,0 +1,311 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common/js"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/indexeddb"
+	cdpruntime "github.com/chromedp/cdproto/runtime"
+	"github.com/dop251/goja"
+)
+
+// indexedDBPageSize is the number of entries read at once from an object store.
+const indexedDBPageSize = 100
+
+// Ensure IndexedDB implements the api.IndexedDB interface.
+var _ api.IndexedDB = &IndexedDB{}
+
+// IndexedDB inspects and seeds the IndexedDB databases
+// of the origin of the main frame of a page.
+type IndexedDB struct {
+	ctx          context.Context
+	session      session
+	frameManager *FrameManager
+	logger       *log.Logger
+
+	enabledMu sync.Mutex
+	enabled   bool
+}
+
+// NewIndexedDB returns a new IndexedDB.
+func NewIndexedDB(ctx context.Context, s session, fm *FrameManager, logger *log.Logger) *IndexedDB {
+	return &IndexedDB{
+		ctx:          ctx,
+		session:      s,
+		frameManager: fm,
+		logger:       logger,
+	}
+}
+
+// Clear deletes all the entries of the object store.
+func (db *IndexedDB) Clear(dbName string, storeName string) error {
+	db.logger.Debugf("IndexedDB:Clear", "sid:%v db:%q store:%q", db.session.ID(), dbName, storeName)
+
+	origin, err := db.origin()
+	if err != nil {
+		return fmt.Errorf("clearing IndexedDB object store: %w", err)
+	}
+	action := indexeddb.ClearObjectStore(dbName, storeName).WithSecurityOrigin(origin)
+	if err := action.Do(cdp.WithExecutor(db.ctx, db.session)); err != nil {
+		return fmt.Errorf("clearing IndexedDB object store %q of database %q: %w", storeName, dbName, err)
+	}
+
+	return nil
+}
+
+// Database returns the database and its object stores.
+func (db *IndexedDB) Database(dbName string) (*api.IndexedDBDatabase, error) {
+	db.logger.Debugf("IndexedDB:Database", "sid:%v db:%q", db.session.ID(), dbName)
+
+	origin, err := db.origin()
+	if err != nil {
+		return nil, fmt.Errorf("getting IndexedDB database: %w", err)
+	}
+	action := indexeddb.RequestDatabase(dbName).WithSecurityOrigin(origin)
+	d, err := action.Do(cdp.WithExecutor(db.ctx, db.session))
+	if err != nil {
+		return nil, fmt.Errorf("getting IndexedDB database %q: %w", dbName, err)
+	}
+
+	database := &api.IndexedDBDatabase{
+		Name:         d.Name,
+		Version:      d.Version,
+		ObjectStores: make([]*api.IndexedDBObjectStore, 0, len(d.ObjectStores)),
+	}
+	for _, s := range d.ObjectStores {
+		store := &api.IndexedDBObjectStore{
+			Name:          s.Name,
+			KeyPath:       keyPathOf(s.KeyPath),
+			AutoIncrement: s.AutoIncrement,
+			Indexes:       make([]string, 0, len(s.Indexes)),
+		}
+		for _, i := range s.Indexes {
+			store.Indexes = append(store.Indexes, i.Name)
+		}
+		database.ObjectStores = append(database.ObjectStores, store)
+	}
+
+	return database, nil
+}
+
+// Databases returns the names of the databases.
+func (db *IndexedDB) Databases() ([]string, error) {
+	db.logger.Debugf("IndexedDB:Databases", "sid:%v", db.session.ID())
+
+	origin, err := db.origin()
+	if err != nil {
+		return nil, fmt.Errorf("getting IndexedDB databases: %w", err)
+	}
+	action := indexeddb.RequestDatabaseNames().WithSecurityOrigin(origin)
+	names, err := action.Do(cdp.WithExecutor(db.ctx, db.session))
+	if err != nil {
+		return nil, fmt.Errorf("getting IndexedDB databases: %w", err)
+	}
+
+	return names, nil
+}
+
+// Entries returns the entries of the object store. All the entries
+// are returned unless the options limit them.
+func (db *IndexedDB) Entries(dbName string, storeName string, opts goja.Value) ([]*api.IndexedDBEntry, error) {
+	db.logger.Debugf("IndexedDB:Entries", "sid:%v db:%q store:%q", db.session.ID(), dbName, storeName)
+
+	eopts := NewIndexedDBEntriesOptions()
+	if err := eopts.Parse(db.ctx, opts); err != nil {
+		return nil, fmt.Errorf("parsing IndexedDB entries options: %w", err)
+	}
+	origin, err := db.origin()
+	if err != nil {
+		return nil, fmt.Errorf("reading IndexedDB entries: %w", err)
+	}
+
+	var (
+		entries = []*api.IndexedDBEntry{}
+		skip    = eopts.Skip
+	)
+	for {
+		pageSize := int64(indexedDBPageSize)
+		if n := eopts.Limit - int64(len(entries)); eopts.Limit > 0 && n < pageSize {
+			pageSize = n
+		}
+		action := indexeddb.RequestData(dbName, storeName, eopts.Index, skip, pageSize).
+			WithSecurityOrigin(origin)
+		if eopts.KeyRange != nil {
+			action = action.WithKeyRange(eopts.KeyRange)
+		}
+		data, hasMore, err := action.Do(cdp.WithExecutor(db.ctx, db.session))
+		if err != nil {
+			return nil, fmt.Errorf("reading entries of IndexedDB object store %q of database %q: %w",
+				storeName, dbName, err)
+		}
+		for _, d := range data {
+			e, err := db.entryOf(d)
+			if err != nil {
+				return nil, fmt.Errorf("reading entries of IndexedDB object store %q of database %q: %w",
+					storeName, dbName, err)
+			}
+			entries = append(entries, e)
+		}
+		skip += int64(len(data))
+		if !hasMore || len(data) == 0 || (eopts.Limit > 0 && int64(len(entries)) >= eopts.Limit) {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// Seed puts the entries into the object store. The entries are an array
+// of objects with a key and a value, or the same array in JSON. The key
+// is only used when the object store has no key path. The database and
+// the object store are created with the options if they don't exist.
+func (db *IndexedDB) Seed(dbName string, storeName string, entries goja.Value, opts goja.Value) error {
+	db.logger.Debugf("IndexedDB:Seed", "sid:%v db:%q store:%q", db.session.ID(), dbName, storeName)
+
+	if !gojaValueExists(entries) {
+		return errors.New("seeding IndexedDB: entries must be set")
+	}
+	sopts := NewIndexedDBSeedOptions()
+	if err := sopts.Parse(db.ctx, opts); err != nil {
+		return fmt.Errorf("parsing IndexedDB seed options: %w", err)
+	}
+	f := db.frameManager.MainFrame()
+	if f == nil {
+		return errors.New("seeding IndexedDB: page has no main frame")
+	}
+	if _, err := originOf(f.URL()); err != nil {
+		return fmt.Errorf("seeding IndexedDB: %w", err)
+	}
+	f.waitForExecutionContext(mainWorld)
+
+	var (
+		rt    = k6ext.Runtime(db.ctx)
+		eopts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+		args = []goja.Value{
+			rt.ToValue(dbName),
+			rt.ToValue(storeName),
+			entries,
+			rt.ToValue(map[string]any{
+				"keyPath":       sopts.KeyPath,
+				"autoIncrement": sopts.AutoIncrement,
+			}),
+		}
+	)
+	if _, err := f.evaluate(db.ctx, mainWorld, eopts, rt.ToValue(js.SeedIndexedDB), args...); err != nil {
+		return fmt.Errorf("seeding IndexedDB object store %q of database %q: %w", storeName, dbName, err)
+	}
+
+	return nil
+}
+
+// origin returns the origin of the main frame of the page, and
+// enables the IndexedDB domain the first time it is called.
+func (db *IndexedDB) origin() (string, error) {
+	f := db.frameManager.MainFrame()
+	if f == nil {
+		return "", errors.New("page has no main frame")
+	}
+	origin, err := originOf(f.URL())
+	if err != nil {
+		return "", err
+	}
+
+	db.enabledMu.Lock()
+	defer db.enabledMu.Unlock()
+
+	if db.enabled {
+		return origin, nil
+	}
+	if err := indexeddb.Enable().Do(cdp.WithExecutor(db.ctx, db.session)); err != nil {
+		return "", fmt.Errorf("enabling IndexedDB: %w", err)
+	}
+	db.enabled = true
+
+	return origin, nil
+}
+
+func (db *IndexedDB) entryOf(d *indexeddb.DataEntry) (*api.IndexedDBEntry, error) {
+	var (
+		e   api.IndexedDBEntry
+		err error
+	)
+	if e.Key, err = db.valueOf(d.Key); err != nil {
+		return nil, fmt.Errorf("reading key: %w", err)
+	}
+	if e.PrimaryKey, err = db.valueOf(d.PrimaryKey); err != nil {
+		return nil, fmt.Errorf("reading primary key: %w", err)
+	}
+	if e.Value, err = db.valueOf(d.Value); err != nil {
+		return nil, fmt.Errorf("reading value: %w", err)
+	}
+
+	return &e, nil
+}
+
+// valueOf returns the value of the remote object of an entry. The
+// objects are serialized in the page, and released afterwards.
+// Dates are returned as ISO strings.
+func (db *IndexedDB) valueOf(obj *cdpruntime.RemoteObject) (any, error) {
+	switch {
+	case obj == nil, obj.Type == cdpruntime.TypeUndefined, obj.Subtype == cdpruntime.SubtypeNull:
+		return nil, nil
+	case obj.ObjectID == "":
+		return parseRemoteObject(obj)
+	}
+	defer func() {
+		action := cdpruntime.ReleaseObject(obj.ObjectID)
+		if err := action.Do(cdp.WithExecutor(db.ctx, db.session)); err != nil {
+			db.logger.Debugf("IndexedDB:valueOf", "sid:%v releasing object: %v", db.session.ID(), err)
+		}
+	}()
+
+	action := cdpruntime.
+		CallFunctionOn(`function() { return this instanceof Date ? this.toISOString() : this; }`).
+		WithObjectID(obj.ObjectID).
+		WithReturnByValue(true)
+	res, exc, err := action.Do(cdp.WithExecutor(db.ctx, db.session))
+	if err != nil {
+		return nil, fmt.Errorf("serializing object: %w", err)
+	}
+	if exc != nil {
+		return nil, fmt.Errorf("serializing object: %s", parseExceptionDetails(exc))
+	}
+	if res == nil || len(res.Value) == 0 {
+		return nil, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(res.Value, &v); err != nil {
+		return nil, fmt.Errorf("parsing object: %w", err)
+	}
+
+	return v, nil
+}
+
+// keyPathOf returns the key path as a string, an array
+// of strings, or nil if the object store has no key path.
+func keyPathOf(kp *indexeddb.KeyPath) any {
+	if kp == nil {
+		return nil
+	}
+	switch kp.Type {
+	case indexeddb.KeyPathTypeString:
+		return kp.String
+	case indexeddb.KeyPathTypeArray:
+		return kp.Array
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,145 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/chromedp/cdproto/indexeddb"
+	"github.com/dop251/goja"
+)
+
+// IndexedDBEntriesOptions are the options of IndexedDB.Entries.
+// The entries are read from the index if it is set, and only the
+// entries with keys within the key range are read.
+type IndexedDBEntriesOptions struct {
+	Index    string
+	KeyRange *indexeddb.KeyRange
+	Skip     int64
+	Limit    int64
+}
+
+// NewIndexedDBEntriesOptions returns a new IndexedDBEntriesOptions.
+func NewIndexedDBEntriesOptions() *IndexedDBEntriesOptions {
+	return &IndexedDBEntriesOptions{}
+}
+
+// Parse parses the IndexedDB entries options.
+func (o *IndexedDBEntriesOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+
+	var (
+		rt  = k6ext.Runtime(ctx)
+		obj = opts.ToObject(rt)
+		kr  indexeddb.KeyRange
+		err error
+	)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "index":
+			o.Index = obj.Get(k).String()
+		case "lower":
+			if kr.Lower, err = indexedDBKey(obj.Get(k).Export()); err != nil {
+				return fmt.Errorf("parsing lower key: %w", err)
+			}
+		case "upper":
+			if kr.Upper, err = indexedDBKey(obj.Get(k).Export()); err != nil {
+				return fmt.Errorf("parsing upper key: %w", err)
+			}
+		case "lowerOpen":
+			kr.LowerOpen = obj.Get(k).ToBoolean()
+		case "upperOpen":
+			kr.UpperOpen = obj.Get(k).ToBoolean()
+		case "skip":
+			o.Skip = obj.Get(k).ToInteger()
+		case "limit":
+			o.Limit = obj.Get(k).ToInteger()
+		}
+	}
+	if o.Skip < 0 {
+		return errors.New("skip must not be negative")
+	}
+	if o.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	if kr.Lower != nil || kr.Upper != nil {
+		o.KeyRange = &kr
+	}
+
+	return nil
+}
+
+// IndexedDBSeedOptions are the options of IndexedDB.Seed. They are
+// used for creating the object store if it doesn't exist.
+type IndexedDBSeedOptions struct {
+	KeyPath       any  `js:"keyPath"`
+	AutoIncrement bool `js:"autoIncrement"`
+}
+
+// NewIndexedDBSeedOptions returns a new IndexedDBSeedOptions.
+func NewIndexedDBSeedOptions() *IndexedDBSeedOptions {
+	return &IndexedDBSeedOptions{}
+}
+
+// Parse parses the IndexedDB seed options.
+func (o *IndexedDBSeedOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "keyPath":
+			switch v := obj.Get(k).Export().(type) {
+			case string:
+				o.KeyPath = v
+			case []any:
+				for _, p := range v {
+					if _, ok := p.(string); !ok {
+						return fmt.Errorf("keyPath must be a string or an array of strings, got %v", v)
+					}
+				}
+				o.KeyPath = v
+			default:
+				return fmt.Errorf("keyPath must be a string or an array of strings, got %v", v)
+			}
+		case "autoIncrement":
+			o.AutoIncrement = obj.Get(k).ToBoolean()
+		}
+	}
+
+	return nil
+}
+
+// indexedDBKey converts an exported JS value to an IndexedDB key.
+// Numbers, strings, dates and arrays of them are valid keys.
+func indexedDBKey(v any) (*indexeddb.Key, error) {
+	switch v := v.(type) {
+	case int64:
+		return &indexeddb.Key{Type: indexeddb.KeyTypeNumber, Number: float64(v)}, nil
+	case float64:
+		return &indexeddb.Key{Type: indexeddb.KeyTypeNumber, Number: v}, nil
+	case string:
+		return &indexeddb.Key{Type: indexeddb.KeyTypeString, String: v}, nil
+	case time.Time:
+		return &indexeddb.Key{Type: indexeddb.KeyTypeDate, Date: float64(v.UnixMilli())}, nil
+	case []any:
+		key := &indexeddb.Key{Type: indexeddb.KeyTypeArray, Array: make([]*indexeddb.Key, 0, len(v))}
+		for _, e := range v {
+			k, err := indexedDBKey(e)
+			if err != nil {
+				return nil, err
+			}
+			key.Array = append(key.Array, k)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%v (%T) is not a valid IndexedDB key", v, v)
+	}
+}
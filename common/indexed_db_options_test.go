@@ -0,0 +1,112 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/chromedp/cdproto/indexeddb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexedDBEntriesOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opts    string
+		want    *IndexedDBEntriesOptions
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			opts: `undefined`,
+			want: &IndexedDBEntriesOptions{},
+		},
+		{
+			name: "index_skip_limit",
+			opts: `({ index: 'by_name', skip: 5, limit: 10 })`,
+			want: &IndexedDBEntriesOptions{Index: "by_name", Skip: 5, Limit: 10},
+		},
+		{
+			name: "key_range",
+			opts: `({ lower: 1, upper: 'z', upperOpen: true })`,
+			want: &IndexedDBEntriesOptions{
+				KeyRange: &indexeddb.KeyRange{
+					Lower:     &indexeddb.Key{Type: indexeddb.KeyTypeNumber, Number: 1},
+					Upper:     &indexeddb.Key{Type: indexeddb.KeyTypeString, String: "z"},
+					UpperOpen: true,
+				},
+			},
+		},
+		{
+			name:    "invalid_key",
+			opts:    `({ lower: { id: 1 } })`,
+			wantErr: "parsing lower key",
+		},
+		{
+			name:    "negative_limit",
+			opts:    `({ limit: -1 })`,
+			wantErr: "limit must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vu := k6test.NewVU(t)
+			v, err := vu.Runtime().RunString(tt.opts)
+			require.NoError(t, err)
+
+			opts := NewIndexedDBEntriesOptions()
+			err = opts.Parse(vu.Context(), v)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, opts)
+		})
+	}
+}
+
+func TestIndexedDBSeedOptionsParse(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	rt := vu.Runtime()
+
+	opts := NewIndexedDBSeedOptions()
+	require.NoError(t, opts.Parse(vu.Context(), rt.ToValue(map[string]any{
+		"keyPath":       "id",
+		"autoIncrement": true,
+	})))
+	assert.Equal(t, &IndexedDBSeedOptions{KeyPath: "id", AutoIncrement: true}, opts)
+
+	opts = NewIndexedDBSeedOptions()
+	err := opts.Parse(vu.Context(), rt.ToValue(map[string]any{"keyPath": 1}))
+	require.ErrorContains(t, err, "keyPath must be a string or an array of strings")
+}
+
+func TestIndexedDBKey(t *testing.T) {
+	t.Parallel()
+
+	date := time.Date(2023, time.January, 2, 3, 4, 5, 0, time.UTC)
+	key, err := indexedDBKey([]any{int64(1), 2.5, "a", date})
+	require.NoError(t, err)
+	assert.Equal(t, &indexeddb.Key{
+		Type: indexeddb.KeyTypeArray,
+		Array: []*indexeddb.Key{
+			{Type: indexeddb.KeyTypeNumber, Number: 1},
+			{Type: indexeddb.KeyTypeNumber, Number: 2.5},
+			{Type: indexeddb.KeyTypeString, String: "a"},
+			{Type: indexeddb.KeyTypeDate, Date: float64(date.UnixMilli())},
+		},
+	}, key)
+
+	_, err = indexedDBKey(true)
+	require.ErrorContains(t, err, "is not a valid IndexedDB key")
+}
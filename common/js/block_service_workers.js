@@ -0,0 +1,9 @@
+if (navigator.serviceWorker) {
+  navigator.serviceWorker.register = async () => {
+    // A blocked registration is rejected as browsers do when service workers are disabled.
+    throw new DOMException(
+      'Service worker registration was blocked by the serviceWorkers browser context option',
+      'SecurityError',
+    );
+  };
+}
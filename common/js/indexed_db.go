@@ -0,0 +1,11 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// SeedIndexedDB puts the entries into an object store of an IndexedDB
+// database, and creates the database and the object store if needed.
+//
+//go:embed seed_indexed_db.js
+var SeedIndexedDB string
@@ -0,0 +1,19 @@
+(origins, binding) => {
+  let storage;
+  try {
+    storage = window.localStorage;
+  } catch (e) {
+    return; // opaque origins can't access the local storage.
+  }
+  const origin = origins.find(o => o.origin === location.origin);
+  if (!origin || !storage) {
+    return;
+  }
+  for (const { name, value } of origin.localStorage || []) {
+    if (storage.getItem(name) === null) {
+      storage.setItem(name, value);
+    }
+  }
+  // let k6 know that the origin is restored so that it's not restored again.
+  window[binding](JSON.stringify({ type: 'storageStateRestored', origin: origin.origin }));
+}
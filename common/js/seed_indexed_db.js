@@ -0,0 +1,55 @@
+async (dbName, storeName, entries, opts) => {
+  if (typeof entries === 'string') {
+    entries = JSON.parse(entries);
+  }
+  if (!Array.isArray(entries)) {
+    throw new Error('entries must be an array of { key, value } objects');
+  }
+  const result = (request) => new Promise((resolve, reject) => {
+    request.onsuccess = () => resolve(request.result);
+    request.onerror = () => reject(request.error);
+  });
+  const open = (version) => {
+    const request = version === undefined
+      ? indexedDB.open(dbName)
+      : indexedDB.open(dbName, version);
+    request.onupgradeneeded = () => {
+      const db = request.result;
+      if (db.objectStoreNames.contains(storeName)) {
+        return;
+      }
+      const params = { autoIncrement: !!opts.autoIncrement };
+      if (opts.keyPath) {
+        params.keyPath = opts.keyPath;
+      }
+      db.createObjectStore(storeName, params);
+    };
+    return result(request);
+  };
+
+  // an object store can only be created while upgrading the database.
+  let db = await open();
+  if (!db.objectStoreNames.contains(storeName)) {
+    const version = db.version + 1;
+    db.close();
+    db = await open(version);
+  }
+  try {
+    const tx = db.transaction(storeName, 'readwrite');
+    const store = tx.objectStore(storeName);
+    for (const { key, value } of entries) {
+      if (store.keyPath === null && key !== undefined) {
+        store.put(value, key);
+      } else {
+        store.put(value);
+      }
+    }
+    await new Promise((resolve, reject) => {
+      tx.oncomplete = () => resolve();
+      tx.onerror = () => reject(tx.error);
+      tx.onabort = () => reject(tx.error);
+    });
+  } finally {
+    db.close();
+  }
+}
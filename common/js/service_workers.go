@@ -0,0 +1,11 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// BlockServiceWorkersScript stops the pages from registering
+// service workers.
+//
+//go:embed block_service_workers.js
+var BlockServiceWorkersScript string
@@ -0,0 +1,12 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// RestoreStorageState restores the local storage of the origin of the
+// page from the origins of a storage state. Items that the page already
+// has are kept. It reports the restored origin through the given binding.
+//
+//go:embed restore_storage_state.js
+var RestoreStorageState string
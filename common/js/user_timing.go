@@ -0,0 +1,11 @@
+package js
+
+import (
+	_ "embed"
+)
+
+// UserTimingScript sends the user timing measures of
+// the current website through the web vital binding.
+//
+//go:embed user_timing.js
+var UserTimingScript string
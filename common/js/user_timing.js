@@ -0,0 +1,16 @@
+(() => {
+  if (typeof PerformanceObserver === 'undefined') {
+    return;
+  }
+  new PerformanceObserver((list) => {
+    for (const entry of list.getEntries()) {
+      const m = {
+        type: 'userTiming',
+        name: entry.name,
+        value: entry.duration,
+        url: window.location.href,
+      }
+      window.k6browserSendWebVitalMetric(JSON.stringify(m))
+    }
+  }).observe({ type: 'measure', buffered: true });
+})();
@@ -12,6 +12,27 @@ function print(metric) {
   window.k6browserSendWebVitalMetric(JSON.stringify(m))
 }
 
+function printNavigationTiming(name, value, navigationType) {
+  const m = {
+    type: 'navigationTiming',
+    name: name,
+    value: value,
+    navigationType: navigationType,
+    url: window.location.href,
+  }
+  window.k6browserSendWebVitalMetric(JSON.stringify(m))
+}
+
+function printLongTask(type, value, navigationType) {
+  const m = {
+    type: type,
+    value: value,
+    navigationType: navigationType,
+    url: window.location.href,
+  }
+  window.k6browserSendWebVitalMetric(JSON.stringify(m))
+}
+
 function load() {
   webVitals.onCLS(print);
   webVitals.onFID(print);
@@ -22,4 +43,78 @@ function load() {
   webVitals.onTTFB(print);
 }
 
+function loadNavigationTiming() {
+  // Only measure the navigations of the pages, not of their frames.
+  if (window !== window.top) {
+    return;
+  }
+  const navigation = () => performance.getEntriesByType('navigation')[0];
+
+  window.addEventListener('load', () => {
+    // The load event ends after its listeners are called.
+    setTimeout(() => {
+      const nav = navigation();
+      if (!nav) {
+        return;
+      }
+      printNavigationTiming('domInteractive', nav.domInteractive, nav.type);
+      printNavigationTiming('domContentLoaded', nav.domContentLoadedEventEnd, nav.type);
+      printNavigationTiming('load', nav.loadEventEnd, nav.type);
+    }, 0);
+  });
+
+  new PerformanceObserver((list) => {
+    const nav = navigation();
+    for (const entry of list.getEntriesByName('first-paint')) {
+      printNavigationTiming('firstPaint', entry.startTime, nav ? nav.type : 'navigate');
+    }
+  }).observe({ type: 'paint', buffered: true });
+}
+
+// loadLongTasks reports the long tasks of the page and the total blocking
+// time of its navigation. Unlike the lab TBT that is measured until the time
+// to interactive, the total blocking time here is measured from the first
+// contentful paint until the load event.
+function loadLongTasks() {
+  // Only measure the main thread of the pages, not of their frames.
+  if (window !== window.top) {
+    return;
+  }
+  // The part of a long task that is longer than
+  // this blocks the main thread.
+  const blockingThreshold = 50;
+  const longTasks = [];
+
+  const onLongTasks = (entries) => {
+    for (const entry of entries) {
+      printLongTask('longTask', entry.duration);
+      longTasks.push(entry);
+    }
+  };
+  const observer = new PerformanceObserver((list) => onLongTasks(list.getEntries()));
+  observer.observe({ type: 'longtask', buffered: true });
+
+  window.addEventListener('load', () => {
+    setTimeout(() => {
+      onLongTasks(observer.takeRecords());
+      const nav = performance.getEntriesByType('navigation')[0];
+      const fcp = performance.getEntriesByName('first-contentful-paint')[0];
+      const start = fcp ? fcp.startTime : 0;
+      const end = nav && nav.loadEventEnd > 0 ? nav.loadEventEnd : performance.now();
+
+      // Only the part of the tasks that is within the window counts,
+      // so the tasks that span the first contentful paint are clipped.
+      let tbt = 0;
+      for (const task of longTasks) {
+        const taskStart = Math.max(task.startTime, start);
+        const taskEnd = Math.min(task.startTime + task.duration, end);
+        tbt += Math.max(0, taskEnd - taskStart - blockingThreshold);
+      }
+      printLongTask('totalBlockingTime', tbt, nav ? nav.type : 'navigate');
+    }, 0);
+  });
+}
+
 load();
+loadNavigationTiming();
+loadLongTasks();
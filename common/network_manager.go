@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
@@ -30,18 +31,23 @@ import (
 // Ensure NetworkManager implements the EventEmitter interface.
 var _ EventEmitter = &NetworkManager{}
 
+// errRequestBlocked is returned for the requests blocked by the browser context.
+var errRequestBlocked = errors.New("request blocked")
+
 // NetworkManager manages all frames in HTML document.
 type NetworkManager struct {
 	BaseEventEmitter
 
-	ctx          context.Context
-	logger       *log.Logger
-	session      session
-	parent       *NetworkManager
-	frameManager *FrameManager
-	credentials  *Credentials
-	resolver     k6netext.Resolver
-	vu           k6modules.VU
+	ctx              context.Context
+	logger           *log.Logger
+	session          session
+	parent           *NetworkManager
+	frameManager     *FrameManager
+	credentials      *Credentials
+	proxyCredentials *Credentials
+	resolver         k6netext.Resolver
+	vu               k6modules.VU
+	k6Metrics        *k6ext.CustomMetrics
 
 	// TODO: manage inflight requests separately (move them between the two maps
 	// as they transition from inflight -> completed)
@@ -50,8 +56,16 @@ type NetworkManager struct {
 
 	attemptedAuth map[fetch.RequestID]bool
 
+	webSockets   map[network.RequestID]*WebSocket
+	webSocketsMu sync.RWMutex
+
+	// lastEventSourceMsg is the time of the last message
+	// received by each EventSource stream.
+	lastEventSourceMsg map[network.RequestID]time.Time
+
 	extraHTTPHeaders               map[string]string
 	offline                        bool
+	networkConditions              *NetworkConditions
 	userCacheDisabled              bool
 	userReqInterceptionEnabled     bool
 	protocolReqInterceptionEnabled bool
@@ -74,15 +88,18 @@ func NewNetworkManager(
 		ctx:              ctx,
 		// TODO: Pass an internal logger instead of basing it on k6's logger?
 		// See https://github.com/grafana/xk6-browser/issues/54
-		logger:           log.New(state.Logger, GetIterationID(ctx)),
-		session:          s,
-		parent:           parent,
-		frameManager:     fm,
-		resolver:         resolver,
-		vu:               vu,
-		reqIDToRequest:   make(map[network.RequestID]*Request),
-		attemptedAuth:    make(map[fetch.RequestID]bool),
-		extraHTTPHeaders: make(map[string]string),
+		logger:             log.New(state.Logger, GetIterationID(ctx)),
+		session:            s,
+		parent:             parent,
+		frameManager:       fm,
+		resolver:           resolver,
+		vu:                 vu,
+		k6Metrics:          k6ext.GetCustomMetrics(ctx),
+		reqIDToRequest:     make(map[network.RequestID]*Request),
+		attemptedAuth:      make(map[fetch.RequestID]bool),
+		webSockets:         make(map[network.RequestID]*WebSocket),
+		lastEventSourceMsg: make(map[network.RequestID]time.Time),
+		extraHTTPHeaders:   make(map[string]string),
 	}
 	m.initEvents()
 	if err := m.initDomains(); err != nil {
@@ -140,6 +157,7 @@ func (m *NetworkManager) deleteRequestByID(reqID network.RequestID) {
 	m.reqsMu.Lock()
 	defer m.reqsMu.Unlock()
 	delete(m.reqIDToRequest, reqID)
+	delete(m.lastEventSourceMsg, reqID)
 }
 
 func (m *NetworkManager) emitRequestMetrics(req *Request) {
@@ -261,6 +279,14 @@ func (m *NetworkManager) emitResponseMetrics(resp *Response, req *Request) {
 	}
 }
 
+// recordHAR records the request in the HAR files of the page.
+func (m *NetworkManager) recordHAR(req *Request, end time.Time) {
+	if m.frameManager == nil || m.frameManager.page == nil {
+		return
+	}
+	m.frameManager.page.recordHAR(req, end)
+}
+
 func (m *NetworkManager) handleRequestRedirect(req *Request, redirectResponse *network.Response, timestamp *cdp.MonotonicTime) {
 	resp := NewHTTPResponse(m.ctx, req, redirectResponse, timestamp)
 	req.responseMu.Lock()
@@ -269,6 +295,7 @@ func (m *NetworkManager) handleRequestRedirect(req *Request, redirectResponse *n
 	req.redirectChain = append(req.redirectChain, req)
 
 	m.emitResponseMetrics(resp, req)
+	m.recordHAR(req, resp.wallTime)
 	m.deleteRequestByID(req.requestID)
 
 	/*
@@ -307,12 +334,27 @@ func (m *NetworkManager) initEvents() {
 		cdproto.EventNetworkResponseReceived,
 		cdproto.EventFetchRequestPaused,
 		cdproto.EventFetchAuthRequired,
+		cdproto.EventNetworkWebSocketCreated,
+		cdproto.EventNetworkWebSocketWillSendHandshakeRequest,
+		cdproto.EventNetworkWebSocketHandshakeResponseReceived,
+		cdproto.EventNetworkWebSocketFrameSent,
+		cdproto.EventNetworkWebSocketFrameReceived,
+		cdproto.EventNetworkWebSocketFrameError,
+		cdproto.EventNetworkWebSocketClosed,
+		cdproto.EventNetworkEventSourceMessageReceived,
 	}, chHandler)
 
 	go func() {
 		for m.handleEvents(chHandler) {
 		}
 	}()
+	go func() {
+		select {
+		case <-m.ctx.Done():
+		case <-m.session.Done():
+			m.closeWebSockets()
+		}
+	}()
 }
 
 func (m *NetworkManager) handleEvents(in <-chan Event) bool {
@@ -337,9 +379,27 @@ func (m *NetworkManager) handleEvents(in <-chan Event) bool {
 		case *network.EventResponseReceived:
 			m.onResponseReceived(ev)
 		case *fetch.EventRequestPaused:
-			m.onRequestPaused(ev)
+			// The route handlers run on the event loop, so don't
+			// block the other network events while waiting for them.
+			go m.onRequestPaused(ev)
 		case *fetch.EventAuthRequired:
 			m.onAuthRequired(ev)
+		case *network.EventWebSocketCreated:
+			m.onWebSocketCreated(ev)
+		case *network.EventWebSocketWillSendHandshakeRequest:
+			m.onWebSocketWillSendHandshakeRequest(ev)
+		case *network.EventWebSocketHandshakeResponseReceived:
+			m.onWebSocketHandshakeResponseReceived(ev)
+		case *network.EventWebSocketFrameSent:
+			m.onWebSocketFrame(ev.RequestID, ev.Response, EventWebSocketFrameSent)
+		case *network.EventWebSocketFrameReceived:
+			m.onWebSocketFrame(ev.RequestID, ev.Response, EventWebSocketFrameReceived)
+		case *network.EventWebSocketFrameError:
+			m.onWebSocketFrameError(ev)
+		case *network.EventWebSocketClosed:
+			m.onWebSocketClosed(ev)
+		case *network.EventEventSourceMessageReceived:
+			m.onEventSourceMessageReceived(ev)
 		}
 	}
 	return true
@@ -353,6 +413,7 @@ func (m *NetworkManager) onLoadingFailed(event *network.EventLoadingFailed) {
 	}
 	req.setErrorText(event.ErrorText)
 	req.responseEndTiming = float64(event.Timestamp.Time().Unix()-req.timestamp.Unix()) * 1000
+	m.recordHAR(req, event.Timestamp.Time().Add(req.offset))
 	m.deleteRequestByID(event.RequestID)
 	m.frameManager.requestFailed(req, event.Canceled)
 }
@@ -384,6 +445,7 @@ func (m *NetworkManager) onLoadingFinished(event *network.EventLoadingFinished)
 		req.responseMu.RLock()
 		m.emitResponseMetrics(req.response, req)
 		req.responseMu.RUnlock()
+		m.recordHAR(req, event.Timestamp.Time().Add(req.offset))
 	}
 	m.deleteRequestByID(event.RequestID)
 	m.frameManager.requestFinished(req)
@@ -394,12 +456,15 @@ func isInternalURL(u *url.URL) bool {
 }
 
 func (m *NetworkManager) onRequest(event *network.EventRequestWillBeSent, interceptionID string) {
-	var redirectChain []*Request = nil
+	var (
+		redirectChain []*Request = nil
+		redirectedReq *Request
+	)
 	if event.RedirectResponse != nil {
-		req := m.requestFromID(event.RequestID)
-		if req != nil {
-			m.handleRequestRedirect(req, event.RedirectResponse, event.Timestamp)
-			redirectChain = req.redirectChain
+		redirectedReq = m.requestFromID(event.RequestID)
+		if redirectedReq != nil {
+			m.handleRequestRedirect(redirectedReq, event.RedirectResponse, event.Timestamp)
+			redirectChain = redirectedReq.redirectChain
 		}
 	} else {
 		redirectChain = make([]*Request, 0)
@@ -429,6 +494,9 @@ func (m *NetworkManager) onRequest(event *network.EventRequestWillBeSent, interc
 		m.logger.Errorf("NetworkManager", "creating request: %s", err)
 		return
 	}
+	if redirectedReq != nil {
+		redirectedReq.setRedirectedTo(req)
+	}
 	// Skip data and blob URLs, since they're internal to the browser.
 	if isInternalURL(req.url) {
 		m.logger.Debugf("NetworkManager", "skipping request handling of %s URL", req.url.Scheme)
@@ -438,6 +506,9 @@ func (m *NetworkManager) onRequest(event *network.EventRequestWillBeSent, interc
 	m.reqIDToRequest[event.RequestID] = req
 	m.reqsMu.Unlock()
 	m.emitRequestMetrics(req)
+	if event.Type == network.ResourceTypeEventSource && m.k6Metrics != nil {
+		m.emitEventSourceMetric(req.URL(), m.k6Metrics.SSEStreams, 1, req.wallTime)
+	}
 	m.frameManager.requestStarted(req)
 }
 
@@ -447,16 +518,27 @@ func (m *NetworkManager) onRequestPaused(event *fetch.EventRequestPaused) {
 	defer m.logger.Debugf("NetworkManager:onRequestPaused:return",
 		"sid:%s url:%v", m.session.ID(), event.Request.URL)
 
-	var failErr error
+	var (
+		failErr error
+		routed  bool
+	)
 
 	defer func() {
+		if routed {
+			return
+		}
 		if failErr != nil {
 			action := fetch.FailRequest(event.RequestID, network.ErrorReasonBlockedByClient)
 			if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
 				m.logger.Errorf("NetworkManager:onRequestPaused",
 					"interrupting request: %s", err)
 			} else {
-				m.logger.Warnf("NetworkManager:onRequestPaused",
+				// Requests blocked by the browser context are expected, so don't warn about them.
+				logf := m.logger.Warnf
+				if errors.Is(failErr, errRequestBlocked) {
+					logf = m.logger.Debugf
+				}
+				logf("NetworkManager:onRequestPaused",
 					"request %s %s was interrupted: %s", event.Request.Method, event.Request.URL, failErr)
 				return
 			}
@@ -468,12 +550,61 @@ func (m *NetworkManager) onRequestPaused(event *fetch.EventRequestPaused) {
 		}
 	}()
 
-	purl, err := url.Parse(event.Request.URL)
-	if err != nil {
-		m.logger.Errorf("NetworkManager:onRequestPaused",
-			"parsing URL %q: %s", event.Request.URL, err)
+	if failErr = m.checkBlockedRequest(event.Request.URL); failErr != nil {
+		return
+	}
+	if failErr = m.checkBlockedByContext(event); failErr != nil {
 		return
 	}
+	routed = m.routeRequest(event)
+}
+
+// checkBlockedByContext returns an error if the request is blocked by
+// the blockedRequests option of the browser context, and counts it.
+func (m *NetworkManager) checkBlockedByContext(event *fetch.EventRequestPaused) error {
+	if m.frameManager == nil || m.frameManager.page == nil {
+		return nil
+	}
+	bctx := m.frameManager.page.browserCtx
+	if bctx == nil || bctx.opts == nil {
+		return nil
+	}
+	reason := bctx.opts.BlockedRequests.reason(event.ResourceType, event.Request.URL)
+	if reason == "" {
+		return nil
+	}
+	m.emitRequestBlockedMetric(event.Request.URL, reason)
+
+	return fmt.Errorf("%w by %s", errRequestBlocked, reason)
+}
+
+func (m *NetworkManager) emitRequestBlockedMetric(url, reason string) {
+	if m.k6Metrics == nil {
+		return
+	}
+	state := m.vu.State()
+
+	tags := state.Tags.GetCurrentValues().Tags.With("reason", reason)
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags = tags.With("url", url)
+	}
+
+	k6metrics.PushIfNotDone(m.ctx, state.Samples, k6metrics.Sample{
+		TimeSeries: k6metrics.TimeSeries{Metric: m.k6Metrics.RequestsBlocked, Tags: tags},
+		Value:      1,
+		Time:       time.Now(),
+	})
+}
+
+// checkBlockedRequest returns an error if the host or the IP
+// address of the URL is blocked by the k6 options.
+func (m *NetworkManager) checkBlockedRequest(rawURL string) error {
+	purl, err := url.Parse(rawURL)
+	if err != nil {
+		m.logger.Errorf("NetworkManager:checkBlockedRequest",
+			"parsing URL %q: %s", rawURL, err)
+		return nil
+	}
 
 	var (
 		host  = purl.Hostname()
@@ -481,22 +612,96 @@ func (m *NetworkManager) onRequestPaused(event *fetch.EventRequestPaused) {
 		state = m.vu.State()
 	)
 	if ip != nil {
-		failErr = checkBlockedIPs(ip, state.Options.BlacklistIPs)
-		return
+		return checkBlockedIPs(ip, state.Options.BlacklistIPs)
 	}
-	failErr = checkBlockedHosts(host, state.Options.BlockedHostnames.Trie)
-	if failErr != nil {
-		return
+	if err := checkBlockedHosts(host, state.Options.BlockedHostnames.Trie); err != nil {
+		return err
 	}
 
 	// Do one last check of the resolved IP
 	ip, err = m.resolver.LookupIP(host)
 	if err != nil {
-		m.logger.Debugf("NetworkManager:onRequestPaused",
+		m.logger.Debugf("NetworkManager:checkBlockedRequest",
 			"resolving %q: %s", host, err)
-		return
+		return nil
 	}
-	failErr = checkBlockedIPs(ip, state.Options.BlacklistIPs)
+
+	return checkBlockedIPs(ip, state.Options.BlacklistIPs)
+}
+
+// routeRequest hands the paused request over to the matching route
+// handlers of the page, starting with the most recently registered one,
+// and then to the matching route handlers of the browser context.
+// It reports whether one of the handlers aborted, continued or fulfilled
+// the request. Otherwise, the request should be continued as is.
+func (m *NetworkManager) routeRequest(event *fetch.EventRequestPaused) bool {
+	if m.frameManager == nil || m.frameManager.page == nil {
+		return false
+	}
+	var (
+		page   = m.frameManager.page
+		routes = page.routesFor(event.Request.URL)
+	)
+	if page.browserCtx != nil {
+		routes = append(routes, page.browserCtx.routesFor(event.Request.URL)...)
+	}
+	if len(routes) == 0 {
+		return false
+	}
+
+	req, err := m.pausedRequest(event)
+	if err != nil {
+		m.logger.Errorf("NetworkManager:routeRequest", "creating request: %s", err)
+		return false
+	}
+
+	route := NewRoute(m.ctx, m.session, req, event.RequestID, m.logger)
+	for _, rh := range routes {
+		if err := rh.route(m.ctx, route); err != nil {
+			m.logger.Errorf("NetworkManager:routeRequest",
+				"url:%s method:%s: %s", event.Request.URL, event.Request.Method, err)
+		}
+		if route.isHandled() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pausedRequest returns the request of the paused event. Since the
+// Fetch.requestPaused event might arrive before the corresponding
+// Network.requestWillBeSent event, it creates the request from the
+// paused event if it's not known yet.
+func (m *NetworkManager) pausedRequest(event *fetch.EventRequestPaused) (*Request, error) {
+	if req := m.requestFromID(event.NetworkID); req != nil {
+		return req, nil
+	}
+
+	var frame *Frame
+	if event.FrameID != "" {
+		frame = m.frameManager.getFrameByID(event.FrameID)
+	}
+	var (
+		now       = time.Now()
+		timestamp = cdp.MonotonicTime(now)
+		wallTime  = cdp.TimeSinceEpoch(now)
+	)
+
+	return NewRequest(m.ctx, NewRequestParams{
+		event: &network.EventRequestWillBeSent{
+			RequestID: event.NetworkID,
+			Request:   event.Request,
+			Timestamp: &timestamp,
+			WallTime:  &wallTime,
+			Type:      event.ResourceType,
+			FrameID:   event.FrameID,
+		},
+		frame:             frame,
+		redirectChain:     make([]*Request, 0),
+		interceptionID:    event.RequestID.String(),
+		allowInterception: m.userReqInterceptionEnabled,
+	})
 }
 
 func checkBlockedHosts(host string, blockedHosts *k6types.HostnameTrie) error {
@@ -522,17 +727,21 @@ func checkBlockedIPs(ip net.IP, blockedIPs []*k6lib.IPNet) error {
 
 func (m *NetworkManager) onAuthRequired(event *fetch.EventAuthRequired) {
 	var (
-		res = fetch.AuthChallengeResponseResponseDefault
-		rid = event.RequestID
+		res         = fetch.AuthChallengeResponseResponseDefault
+		rid         = event.RequestID
+		credentials = m.credentials
 
 		username, password string
 	)
+	if event.AuthChallenge != nil && event.AuthChallenge.Source == fetch.AuthChallengeSourceProxy {
+		credentials = m.proxyCredentials
+	}
 
 	switch {
 	case m.attemptedAuth[rid]:
 		delete(m.attemptedAuth, rid)
 		res = fetch.AuthChallengeResponseResponseCancelAuth
-	case m.credentials != nil:
+	case credentials != nil:
 		// TODO: remove requests from attemptedAuth when:
 		//       - request is redirected
 		//       - loading finished
@@ -541,7 +750,7 @@ func (m *NetworkManager) onAuthRequired(event *fetch.EventAuthRequired) {
 		// The Fetch.AuthChallengeResponse docs mention username and password should only be set
 		// if the response is ProvideCredentials.
 		// See: https://chromedevtools.github.io/devtools-protocol/tot/Fetch/#type-AuthChallengeResponse
-		username, password = m.credentials.Username, m.credentials.Password
+		username, password = credentials.Username, credentials.Password
 	}
 	err := fetch.ContinueWithAuth(
 		rid,
@@ -577,6 +786,177 @@ func (m *NetworkManager) onResponseReceived(event *network.EventResponseReceived
 	m.frameManager.requestReceivedResponse(resp)
 }
 
+func (m *NetworkManager) onWebSocketCreated(event *network.EventWebSocketCreated) {
+	m.logger.Debugf("NetworkManager:onWebSocketCreated", "sid:%v rid:%s url:%s", m.session.ID(), event.RequestID, event.URL)
+
+	var pageURL string
+	if m.frameManager != nil {
+		if mf := m.frameManager.MainFrame(); mf != nil {
+			pageURL = mf.URL()
+		}
+	}
+	ws := NewWebSocket(m.ctx, m.session.Done(), event.RequestID, event.URL, pageURL, m.logger)
+
+	m.webSocketsMu.Lock()
+	m.webSockets[event.RequestID] = ws
+	m.webSocketsMu.Unlock()
+
+	if m.frameManager != nil && m.frameManager.page != nil {
+		m.frameManager.page.emit(EventPageWebSocket, ws)
+	}
+}
+
+func (m *NetworkManager) onWebSocketWillSendHandshakeRequest(event *network.EventWebSocketWillSendHandshakeRequest) {
+	if ws := m.webSocketFromID(event.RequestID); ws != nil {
+		ws.handshake(event.Timestamp)
+	}
+}
+
+func (m *NetworkManager) onWebSocketHandshakeResponseReceived(event *network.EventWebSocketHandshakeResponseReceived) {
+	ws := m.webSocketFromID(event.RequestID)
+	if ws == nil {
+		return
+	}
+	connecting := ws.connect(event.Timestamp)
+	m.emitWebSocketMetric(ws, m.vu.State().BuiltinMetrics.WSSessions, 1)
+	m.emitWebSocketMetric(ws, m.vu.State().BuiltinMetrics.WSConnecting, k6metrics.D(connecting))
+}
+
+func (m *NetworkManager) onWebSocketFrame(rid network.RequestID, frame *network.WebSocketFrame, event string) {
+	ws := m.webSocketFromID(rid)
+	if ws == nil || frame == nil {
+		return
+	}
+
+	metric := m.vu.State().BuiltinMetrics.WSMessagesReceived
+	if event == EventWebSocketFrameSent {
+		metric = m.vu.State().BuiltinMetrics.WSMessagesSent
+	}
+	m.emitWebSocketMetric(ws, metric, 1)
+
+	ws.emit(event, &WebSocketFrame{
+		Opcode:  frame.Opcode,
+		Payload: frame.PayloadData,
+	})
+}
+
+func (m *NetworkManager) onWebSocketFrameError(event *network.EventWebSocketFrameError) {
+	if ws := m.webSocketFromID(event.RequestID); ws != nil {
+		ws.emit(EventWebSocketError, event.ErrorMessage)
+	}
+}
+
+func (m *NetworkManager) onWebSocketClosed(event *network.EventWebSocketClosed) {
+	m.logger.Debugf("NetworkManager:onWebSocketClosed", "sid:%v rid:%s", m.session.ID(), event.RequestID)
+
+	m.webSocketsMu.Lock()
+	ws, ok := m.webSockets[event.RequestID]
+	delete(m.webSockets, event.RequestID)
+	m.webSocketsMu.Unlock()
+	if !ok {
+		return
+	}
+
+	if duration, connected, ok := ws.close(event.Timestamp); ok {
+		if connected {
+			m.emitWebSocketMetric(ws, m.vu.State().BuiltinMetrics.WSSessionDuration, k6metrics.D(duration))
+		}
+		ws.emit(EventWebSocketClose, ws)
+	}
+}
+
+// closeWebSockets closes the WebSockets that are still open when
+// the page is closed, and emits the duration of their sessions.
+func (m *NetworkManager) closeWebSockets() {
+	m.webSocketsMu.Lock()
+	wss := m.webSockets
+	m.webSockets = make(map[network.RequestID]*WebSocket)
+	m.webSocketsMu.Unlock()
+
+	now := cdp.MonotonicTime(time.Now())
+	for _, ws := range wss {
+		if duration, connected, ok := ws.close(&now); ok && connected {
+			m.emitWebSocketMetric(ws, m.vu.State().BuiltinMetrics.WSSessionDuration, k6metrics.D(duration))
+		}
+	}
+}
+
+func (m *NetworkManager) webSocketFromID(reqID network.RequestID) *WebSocket {
+	m.webSocketsMu.RLock()
+	defer m.webSocketsMu.RUnlock()
+	return m.webSockets[reqID]
+}
+
+// emitWebSocketMetric emits a WebSocket metric tagged with the URL
+// of the page that opened the WebSocket.
+func (m *NetworkManager) emitWebSocketMetric(ws *WebSocket, metric *k6metrics.Metric, value float64) {
+	state := m.vu.State()
+
+	tags := state.Tags.GetCurrentValues().Tags
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags = tags.With("url", ws.pageURL)
+	}
+
+	k6metrics.PushIfNotDone(m.ctx, state.Samples, k6metrics.Sample{
+		TimeSeries: k6metrics.TimeSeries{Metric: metric, Tags: tags},
+		Value:      value,
+		Time:       time.Now(),
+	})
+}
+
+func (m *NetworkManager) onEventSourceMessageReceived(event *network.EventEventSourceMessageReceived) {
+	m.logger.Debugf("NetworkManager:onEventSourceMessageReceived",
+		"sid:%v rid:%s event:%s id:%s", m.session.ID(), event.RequestID, event.EventName, event.EventID)
+
+	req := m.requestFromID(event.RequestID)
+	if req == nil {
+		return
+	}
+
+	received := event.Timestamp.Time()
+	m.reqsMu.Lock()
+	last, ok := m.lastEventSourceMsg[event.RequestID]
+	m.lastEventSourceMsg[event.RequestID] = received
+	m.reqsMu.Unlock()
+
+	// The latency of the stream is measured from its request to its first
+	// message, and the interval of the other messages from the previous one.
+	if m.k6Metrics != nil {
+		wallTime := received.Add(req.offset)
+		m.emitEventSourceMetric(req.URL(), m.k6Metrics.SSEMessagesReceived, 1, wallTime)
+		if ok {
+			m.emitEventSourceMetric(req.URL(), m.k6Metrics.SSEMessageInterval, k6metrics.D(received.Sub(last)), wallTime)
+		} else {
+			m.emitEventSourceMetric(req.URL(), m.k6Metrics.SSEMessageLatency, k6metrics.D(received.Sub(req.timestamp)), wallTime)
+		}
+	}
+
+	if m.frameManager != nil && m.frameManager.page != nil {
+		m.frameManager.page.emit(EventPageEventSource, &EventSourceMessage{
+			URL:       req.URL(),
+			EventName: event.EventName,
+			EventID:   event.EventID,
+			Data:      event.Data,
+		})
+	}
+}
+
+// emitEventSourceMetric emits a server-sent events metric tagged with the URL of the stream.
+func (m *NetworkManager) emitEventSourceMetric(url string, metric *k6metrics.Metric, value float64, t time.Time) {
+	state := m.vu.State()
+
+	tags := state.Tags.GetCurrentValues().Tags
+	if state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags = tags.With("url", url)
+	}
+
+	k6metrics.PushIfNotDone(m.ctx, state.Samples, k6metrics.Sample{
+		TimeSeries: k6metrics.TimeSeries{Metric: metric, Tags: tags},
+		Value:      value,
+		Time:       t,
+	})
+}
+
 func (m *NetworkManager) requestFromID(reqID network.RequestID) *Request {
 	m.reqsMu.RLock()
 	defer m.reqsMu.RUnlock()
@@ -644,6 +1024,17 @@ func (m *NetworkManager) Authenticate(credentials *Credentials) {
 	}
 }
 
+// AuthenticateProxy sets the credentials for the proxy authentication.
+func (m *NetworkManager) AuthenticateProxy(credentials *Credentials) {
+	m.proxyCredentials = credentials
+	if credentials != nil {
+		m.userReqInterceptionEnabled = true
+	}
+	if err := m.updateProtocolRequestInterception(); err != nil {
+		k6ext.Panic(m.ctx, "setting proxy authentication credentials: %w", err)
+	}
+}
+
 // ExtraHTTPHeaders returns the currently set extra HTTP request headers.
 func (m *NetworkManager) ExtraHTTPHeaders() goja.Value {
 	rt := m.vu.Runtime()
@@ -665,12 +1056,37 @@ func (m *NetworkManager) SetOfflineMode(offline bool) {
 	}
 	m.offline = offline
 
-	action := network.EmulateNetworkConditions(m.offline, 0, -1, -1)
-	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+	if err := m.emulateNetworkConditions(); err != nil {
 		k6ext.Panic(m.ctx, "setting offline mode: %w", err)
 	}
 }
 
+// SetNetworkConditions emulates the given network conditions.
+// Passing nil turns off the emulation.
+func (m *NetworkManager) SetNetworkConditions(conditions *NetworkConditions) {
+	m.networkConditions = conditions
+
+	if err := m.emulateNetworkConditions(); err != nil {
+		k6ext.Panic(m.ctx, "setting network conditions: %w", err)
+	}
+}
+
+// emulateNetworkConditions applies both the offline mode
+// and the network conditions, as they share the same CDP call.
+func (m *NetworkManager) emulateNetworkConditions() error {
+	latency, download, upload := 0.0, -1.0, -1.0
+	if nc := m.networkConditions; nc != nil {
+		latency, download, upload = nc.Latency, nc.DownloadThroughput, nc.UploadThroughput
+	}
+
+	action := network.EmulateNetworkConditions(m.offline, latency, download, upload)
+	if err := action.Do(cdp.WithExecutor(m.ctx, m.session)); err != nil {
+		return fmt.Errorf("emulating network conditions: %w", err)
+	}
+
+	return nil
+}
+
 // SetUserAgent overrides the browser user agent string.
 func (m *NetworkManager) SetUserAgent(userAgent string) {
 	action := emulation.SetUserAgentOverride(userAgent)
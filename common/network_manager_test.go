@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
 	"github.com/grafana/xk6-browser/k6ext/k6test"
 	"github.com/grafana/xk6-browser/log"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/fetch"
 	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
 	"github.com/mailru/easyjson"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,7 +47,8 @@ func newTestNetworkManager(t *testing.T, k6opts k6lib.Options) (*NetworkManager,
 
 	session := &fakeSession{
 		session: &Session{
-			id: "1234",
+			id:   "1234",
+			done: make(chan struct{}),
 		},
 	}
 
@@ -203,6 +207,287 @@ func TestOnRequestPausedBlockedIPs(t *testing.T) {
 	}
 }
 
+// newTestRouteHandler returns a route handler for the url
+// and the handler, which are evaluated as JS expressions.
+func newTestRouteHandler(t *testing.T, nm *NetworkManager, url, handler string) *routeHandler {
+	t.Helper()
+
+	rt := nm.vu.Runtime()
+	u, err := rt.RunString(url)
+	require.NoError(t, err)
+	h, err := rt.RunString(handler)
+	require.NoError(t, err)
+	fn, ok := goja.AssertFunction(h)
+	require.True(t, ok)
+
+	rh, err := newRouteHandler(nm.ctx, nil, u, api.RouteHandler{
+		Value: h,
+		Call: func(r api.Route) error {
+			_, err := fn(goja.Undefined(), rt.ToValue(r))
+			return err
+		},
+	})
+	require.NoError(t, err)
+
+	return rh
+}
+
+// onRequestPausedOnEventLoop sets up the routes on the event loop,
+// then handles the paused request in a promise, while the event loop
+// runs the route handlers. It closes the route handlers once it's done.
+func onRequestPausedOnEventLoop(
+	t *testing.T, nm *NetworkManager, ev *fetch.EventRequestPaused, setup func() []*routeHandler,
+) {
+	t.Helper()
+
+	vu, ok := nm.vu.(*k6test.VU)
+	require.True(t, ok)
+	err := vu.Loop.Start(func() error {
+		routes := setup()
+		k6ext.Promise(nm.ctx, func() (any, error) {
+			nm.onRequestPaused(ev)
+			for _, rh := range routes {
+				rh.close()
+			}
+			return nil, nil
+		})
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestOnRequestPausedRoutes(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, reqURL, url, handler string
+		expCDPCalls                []string
+	}{
+		{
+			name:        "abort",
+			reqURL:      "http://host.com/api/data",
+			url:         `'**/api/*'`,
+			handler:     `route => route.abort()`,
+			expCDPCalls: []string{"Fetch.failRequest"},
+		},
+		{
+			name:        "continue",
+			reqURL:      "http://host.com/api/data",
+			url:         `'**/api/*'`,
+			handler:     `route => route.continue({ method: 'POST', postData: 'x=1' })`,
+			expCDPCalls: []string{"Fetch.continueRequest"},
+		},
+		{
+			name:        "fulfill",
+			reqURL:      "http://host.com/api/data",
+			url:         `'**/api/*'`,
+			handler:     `route => route.fulfill({ status: 404, contentType: 'text/plain', body: 'not found' })`,
+			expCDPCalls: []string{"Fetch.fulfillRequest"},
+		},
+		{
+			name:        "unhandled_continue",
+			reqURL:      "http://host.com/api/data",
+			url:         `'**/api/*'`,
+			handler:     `route => {}`,
+			expCDPCalls: []string{"Fetch.continueRequest"},
+		},
+		{
+			name:        "unmatched_continue",
+			reqURL:      "http://host.com/static/app.js",
+			url:         `'**/api/*'`,
+			handler:     `route => route.abort()`,
+			expCDPCalls: []string{"Fetch.continueRequest"},
+		},
+		{
+			name:        "predicate",
+			reqURL:      "http://host.com/api/data",
+			url:         `url => url.endsWith('/data')`,
+			handler:     `route => route.abort()`,
+			expCDPCalls: []string{"Fetch.failRequest"},
+		},
+		{
+			name:        "unmatched_predicate_continue",
+			reqURL:      "http://host.com/api/data",
+			url:         `url => url.endsWith('.js')`,
+			handler:     `route => route.abort()`,
+			expCDPCalls: []string{"Fetch.continueRequest"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nm, session := newTestNetworkManager(t, k6lib.Options{})
+			ev := &fetch.EventRequestPaused{
+				RequestID: "1234",
+				NetworkID: "5678",
+				Request: &network.Request{
+					Method: "GET",
+					URL:    tc.reqURL,
+				},
+			}
+
+			onRequestPausedOnEventLoop(t, nm, ev, func() []*routeHandler {
+				rh := newTestRouteHandler(t, nm, tc.url, tc.handler)
+				nm.frameManager = &FrameManager{
+					page: &Page{routes: []*routeHandler{rh}},
+				}
+				return []*routeHandler{rh}
+			})
+
+			assert.Equal(t, tc.expCDPCalls, session.cdpCalls)
+		})
+	}
+}
+
+func TestOnRequestPausedRoutePrecedence(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, pageHandler, contextHandler string
+		expCDPCalls                       []string
+	}{
+		{
+			name:           "page_first",
+			pageHandler:    `route => route.fulfill({ body: 'page' })`,
+			contextHandler: `route => route.abort()`,
+			expCDPCalls:    []string{"Fetch.fulfillRequest"},
+		},
+		{
+			name:           "context_fallback",
+			pageHandler:    `route => {}`,
+			contextHandler: `route => route.abort()`,
+			expCDPCalls:    []string{"Fetch.failRequest"},
+		},
+		{
+			name:           "unhandled_continue",
+			pageHandler:    `route => {}`,
+			contextHandler: `route => {}`,
+			expCDPCalls:    []string{"Fetch.continueRequest"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nm, session := newTestNetworkManager(t, k6lib.Options{})
+			ev := &fetch.EventRequestPaused{
+				RequestID: "1234",
+				NetworkID: "5678",
+				Request: &network.Request{
+					Method: "GET",
+					URL:    "http://host.com/",
+				},
+			}
+
+			onRequestPausedOnEventLoop(t, nm, ev, func() []*routeHandler {
+				var (
+					pageRoute    = newTestRouteHandler(t, nm, `'**/*'`, tc.pageHandler)
+					contextRoute = newTestRouteHandler(t, nm, `'**/*'`, tc.contextHandler)
+				)
+				nm.frameManager = &FrameManager{
+					page: &Page{
+						routes: []*routeHandler{pageRoute},
+						browserCtx: &BrowserContext{
+							routes: []*routeHandler{contextRoute},
+						},
+					},
+				}
+				return []*routeHandler{pageRoute, contextRoute}
+			})
+
+			assert.Equal(t, tc.expCDPCalls, session.cdpCalls)
+		})
+	}
+}
+
+func TestOnRequestPausedBlockedByContext(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, url   string
+		typ         network.ResourceType
+		expCDPCalls []string
+		expReason   string
+	}{
+		{
+			name:        "resource_type",
+			url:         "http://host.com/logo.png",
+			typ:         network.ResourceTypeImage,
+			expCDPCalls: []string{"Fetch.failRequest"},
+			expReason:   "resource_type",
+		},
+		{
+			name:        "url",
+			url:         "http://host.com/analytics.js",
+			typ:         network.ResourceTypeScript,
+			expCDPCalls: []string{"Fetch.failRequest"},
+			expReason:   "url",
+		},
+		{
+			name:        "not_blocked",
+			url:         "http://host.com/",
+			typ:         network.ResourceTypeDocument,
+			expCDPCalls: []string{"Fetch.continueRequest"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			nm, session := newTestNetworkManager(t, k6lib.Options{})
+			nm.k6Metrics = k6ext.RegisterCustomMetrics(k6metrics.NewRegistry())
+			rt := nm.vu.Runtime()
+			blocked := NewBlockedRequests()
+			err := blocked.Parse(nm.ctx, rt.ToValue(map[string]any{
+				"resourceTypes": []string{"image"},
+				"urls":          []string{"**/analytics.js"},
+			}))
+			require.NoError(t, err)
+			nm.frameManager = &FrameManager{
+				page: &Page{
+					browserCtx: &BrowserContext{
+						opts: &BrowserContextOptions{BlockedRequests: blocked},
+					},
+				},
+			}
+			ev := &fetch.EventRequestPaused{
+				RequestID:    "1234",
+				NetworkID:    "5678",
+				ResourceType: tc.typ,
+				Request: &network.Request{
+					Method: "GET",
+					URL:    tc.url,
+				},
+			}
+
+			nm.onRequestPaused(ev)
+
+			assert.Equal(t, tc.expCDPCalls, session.cdpCalls)
+
+			var reasons []string
+			nm.vu.(*k6test.VU).AssertSamples(func(s k6metrics.Sample) { //nolint:forcetypeassert
+				if s.Metric != nm.k6Metrics.RequestsBlocked {
+					return
+				}
+				reason, _ := s.Tags.Get("reason")
+				reasons = append(reasons, reason)
+			})
+			if tc.expReason == "" {
+				assert.Empty(t, reasons)
+			} else {
+				assert.Equal(t, []string{tc.expReason}, reasons)
+			}
+		})
+	}
+}
+
 func TestNetworkManagerEmitRequestResponseMetricsTimingSkew(t *testing.T) {
 	t.Parallel()
 
@@ -291,3 +576,267 @@ func TestNetworkManagerEmitRequestResponseMetricsTimingSkew(t *testing.T) {
 		})
 	}
 }
+
+func TestNetworkManagerWebSocketEvents(t *testing.T) {
+	t.Parallel()
+
+	nm, session := newTestNetworkManager(t, k6lib.Options{
+		SystemTags: k6metrics.NewSystemTagSet(k6metrics.TagURL),
+	})
+	nm.webSockets = make(map[network.RequestID]*WebSocket)
+	page := &Page{BaseEventEmitter: NewBaseEventEmitter(nm.ctx), ctx: nm.ctx, session: session, logger: nm.logger}
+	nm.frameManager = &FrameManager{page: page, mainFrame: &Frame{url: "http://host.test/"}}
+
+	var (
+		vu    = nm.vu.(*k6test.VU) //nolint:forcetypeassert
+		rid   = network.RequestID("ws1")
+		start = time.Now()
+		ts    = func(d time.Duration) *cdp.MonotonicTime {
+			t := cdp.MonotonicTime(start.Add(d))
+			return &t
+		}
+
+		ws     *WebSocket
+		frames []any
+	)
+	err := vu.Loop.Start(func() error {
+		var (
+			listening = make(chan struct{})
+			received  = make(chan struct{})
+		)
+		err := page.On(EventPageWebSocket, func(data any) error {
+			ws = data.(*WebSocket) //nolint:forcetypeassert
+			if err := ws.On(EventWebSocketFrameSent, func(data any) error {
+				frames = append(frames, data)
+				close(received)
+				return nil
+			}); err != nil {
+				return err
+			}
+			close(listening)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		k6ext.Promise(nm.ctx, func() (any, error) {
+			// the page is closed once the frame is handled on the event loop.
+			defer close(session.session.(*Session).done) //nolint:forcetypeassert
+
+			nm.onWebSocketCreated(&network.EventWebSocketCreated{RequestID: rid, URL: "ws://host.test/echo"})
+			select {
+			case <-listening:
+			case <-time.After(time.Second):
+				return nil, nil
+			}
+			nm.onWebSocketWillSendHandshakeRequest(&network.EventWebSocketWillSendHandshakeRequest{
+				RequestID: rid, Timestamp: ts(0),
+			})
+			nm.onWebSocketHandshakeResponseReceived(&network.EventWebSocketHandshakeResponseReceived{
+				RequestID: rid, Timestamp: ts(100 * time.Millisecond),
+			})
+			nm.onWebSocketFrame(rid, &network.WebSocketFrame{Opcode: 1, PayloadData: "hello"}, EventWebSocketFrameSent)
+			nm.onWebSocketFrame(rid, &network.WebSocketFrame{Opcode: 1, PayloadData: "hi"}, EventWebSocketFrameReceived)
+			nm.onWebSocketClosed(&network.EventWebSocketClosed{RequestID: rid, Timestamp: ts(time.Second)})
+			select {
+			case <-received:
+			case <-time.After(time.Second):
+			}
+			return nil, nil
+		})
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, ws, "should handle the websocket event")
+	assert.Equal(t, "ws://host.test/echo", ws.URL())
+	assert.Equal(t, []any{&WebSocketFrame{Opcode: 1, Payload: "hello"}}, frames)
+	assert.True(t, ws.IsClosed())
+	require.ErrorContains(t, ws.On("unknown", nil), "unknown websocket event")
+
+	got := make(map[string]float64)
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		url, _ := s.Tags.Get("url")
+		assert.Equal(t, "http://host.test/", url, "should be tagged with the page URL")
+		got[s.Metric.Name] += s.Value
+	})
+	assert.Equal(t, map[string]float64{
+		"ws_sessions":         1,
+		"ws_connecting":       100,
+		"ws_msgs_sent":        1,
+		"ws_msgs_received":    1,
+		"ws_session_duration": 1000,
+	}, got)
+}
+
+func TestNetworkManagerCloseWebSockets(t *testing.T) {
+	t.Parallel()
+
+	nm, _ := newTestNetworkManager(t, k6lib.Options{})
+	nm.webSockets = make(map[network.RequestID]*WebSocket)
+
+	now := cdp.MonotonicTime(time.Now())
+	nm.onWebSocketCreated(&network.EventWebSocketCreated{RequestID: "open", URL: "ws://host.test/open"})
+	nm.onWebSocketWillSendHandshakeRequest(&network.EventWebSocketWillSendHandshakeRequest{
+		RequestID: "open", Timestamp: &now,
+	})
+	nm.onWebSocketHandshakeResponseReceived(&network.EventWebSocketHandshakeResponseReceived{
+		RequestID: "open", Timestamp: &now,
+	})
+	nm.onWebSocketCreated(&network.EventWebSocketCreated{RequestID: "connecting", URL: "ws://host.test/connecting"})
+	open, connecting := nm.webSocketFromID("open"), nm.webSocketFromID("connecting")
+
+	nm.closeWebSockets()
+
+	assert.True(t, open.IsClosed())
+	assert.True(t, connecting.IsClosed())
+	assert.Empty(t, nm.webSockets)
+
+	var durations int
+	nm.vu.(*k6test.VU).AssertSamples(func(s k6metrics.Sample) { //nolint:forcetypeassert
+		if s.Metric.Name == "ws_session_duration" {
+			durations++
+		}
+	})
+	assert.Equal(t, 1, durations, "should only emit the duration of the connected websocket")
+}
+
+func TestNetworkManagerEventSourceMessages(t *testing.T) {
+	t.Parallel()
+
+	nm, session := newTestNetworkManager(t, k6lib.Options{})
+	nm.k6Metrics = k6ext.RegisterCustomMetrics(k6metrics.NewRegistry())
+	nm.lastEventSourceMsg = make(map[network.RequestID]time.Time)
+	page := &Page{BaseEventEmitter: NewBaseEventEmitter(nm.ctx), ctx: nm.ctx, session: session, logger: nm.logger}
+	nm.frameManager = &FrameManager{page: page}
+
+	var (
+		rid   = network.RequestID("sse1")
+		start = time.Now()
+		ts    = func(d time.Duration) *cdp.MonotonicTime {
+			t := cdp.MonotonicTime(start.Add(d))
+			return &t
+		}
+	)
+	req, err := NewRequest(nm.ctx, NewRequestParams{
+		event: &network.EventRequestWillBeSent{
+			RequestID: rid,
+			Request:   &network.Request{URL: "http://host.test/stream"},
+			Timestamp: ts(0),
+			WallTime:  (*cdp.TimeSinceEpoch)(&start),
+			Type:      network.ResourceTypeEventSource,
+		},
+	})
+	require.NoError(t, err)
+	nm.reqIDToRequest = map[network.RequestID]*Request{rid: req}
+
+	var msgs []*EventSourceMessage
+	err = nm.vu.(*k6test.VU).Loop.Start(func() error { //nolint:forcetypeassert
+		received := make(chan struct{})
+		err := page.On(EventPageEventSource, func(data any) error {
+			msgs = append(msgs, data.(*EventSourceMessage)) //nolint:forcetypeassert
+			if len(msgs) == 2 {
+				close(received)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		k6ext.Promise(nm.ctx, func() (any, error) {
+			// the page is closed once the messages are handled on the event loop.
+			defer close(session.session.(*Session).done) //nolint:forcetypeassert
+
+			nm.onEventSourceMessageReceived(&network.EventEventSourceMessageReceived{
+				RequestID: rid, Timestamp: ts(100 * time.Millisecond), EventName: "message", EventID: "1", Data: "a",
+			})
+			nm.onEventSourceMessageReceived(&network.EventEventSourceMessageReceived{
+				RequestID: rid, Timestamp: ts(300 * time.Millisecond), EventName: "update", EventID: "2", Data: "b",
+			})
+			select {
+			case <-received:
+			case <-time.After(time.Second):
+			}
+			return nil, nil
+		})
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*EventSourceMessage{
+		{URL: "http://host.test/stream", EventName: "message", EventID: "1", Data: "a"},
+		{URL: "http://host.test/stream", EventName: "update", EventID: "2", Data: "b"},
+	}, msgs)
+
+	var (
+		received  float64
+		latencies []float64
+		intervals []float64
+	)
+	nm.vu.(*k6test.VU).AssertSamples(func(s k6metrics.Sample) { //nolint:forcetypeassert
+		switch s.Metric {
+		case nm.k6Metrics.SSEMessagesReceived:
+			received += s.Value
+		case nm.k6Metrics.SSEMessageLatency:
+			latencies = append(latencies, s.Value)
+		case nm.k6Metrics.SSEMessageInterval:
+			intervals = append(intervals, s.Value)
+		}
+	})
+	assert.Equal(t, 2.0, received)
+	assert.Equal(t, []float64{100}, latencies)
+	assert.Equal(t, []float64{200}, intervals)
+}
+
+type authSession struct {
+	session
+	responses []*fetch.AuthChallengeResponse
+}
+
+func (s *authSession) Execute(
+	ctx context.Context, method string, params easyjson.Marshaler, res easyjson.Unmarshaler,
+) error {
+	if p, ok := params.(*fetch.ContinueWithAuthParams); ok {
+		s.responses = append(s.responses, p.AuthChallengeResponse)
+	}
+	return nil
+}
+
+func TestOnAuthRequiredProxy(t *testing.T) {
+	t.Parallel()
+
+	nm, _ := newTestNetworkManager(t, k6lib.Options{})
+	s := &authSession{session: nm.session}
+	nm.session = s
+	nm.attemptedAuth = make(map[fetch.RequestID]bool)
+	nm.credentials = &Credentials{Username: "site", Password: "sitepass"}
+	nm.proxyCredentials = &Credentials{Username: "proxy", Password: "proxypass"}
+
+	authRequired := func(rid fetch.RequestID, source fetch.AuthChallengeSource) {
+		nm.onAuthRequired(&fetch.EventAuthRequired{
+			RequestID:     rid,
+			Request:       &network.Request{URL: "http://host.test/"},
+			AuthChallenge: &fetch.AuthChallenge{Source: source},
+		})
+	}
+	authRequired("1", fetch.AuthChallengeSourceProxy)
+	authRequired("2", fetch.AuthChallengeSourceServer)
+	// a second challenge for the same request means the credentials were rejected.
+	authRequired("1", fetch.AuthChallengeSourceProxy)
+
+	require.Len(t, s.responses, 3)
+	assert.Equal(t, &fetch.AuthChallengeResponse{
+		Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+		Username: "proxy",
+		Password: "proxypass",
+	}, s.responses[0])
+	assert.Equal(t, &fetch.AuthChallengeResponse{
+		Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+		Username: "site",
+		Password: "sitepass",
+	}, s.responses[1])
+	assert.Equal(t, fetch.AuthChallengeResponseResponseCancelAuth, s.responses[2].Response)
+}
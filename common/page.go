@@ -24,7 +24,21 @@ import (
 	"github.com/dop251/goja"
 )
 
-const webVitalBinding = "k6browserSendWebVitalMetric"
+const (
+	webVitalBinding = "k6browserSendWebVitalMetric"
+
+	// The types of the payloads of the timings
+	// sent through the web vital binding.
+	longTaskPayloadType          = "longTask"
+	navigationTimingPayloadType  = "navigationTiming"
+	totalBlockingTimePayloadType = "totalBlockingTime"
+	userTimingPayloadType        = "userTiming"
+
+	// storageStateRestoredPayloadType is the type of the payload sent
+	// through the web vital binding once the local storage of an origin
+	// of the storage state is restored.
+	storageStateRestoredPayloadType = "storageStateRestored"
+)
 
 // Ensure page implements the EventEmitter, Target and Page interfaces.
 var (
@@ -36,6 +50,7 @@ var (
 type Page struct {
 	BaseEventEmitter
 
+	IndexedDB   *IndexedDB
 	Keyboard    *Keyboard
 	Mouse       *Mouse
 	Touchscreen *Touchscreen
@@ -61,11 +76,18 @@ type Page struct {
 	closed   bool
 
 	// TODO: setter change these fields (mutex?)
-	emulatedSize     *EmulatedSize
-	mediaType        MediaType
-	colorScheme      ColorScheme
-	reducedMotion    ReducedMotion
-	extraHTTPHeaders map[string]string
+	emulatedSize      *EmulatedSize
+	mediaType         MediaType
+	colorScheme       ColorScheme
+	reducedMotion     ReducedMotion
+	networkConditions *NetworkConditions
+	extraHTTPHeaders  map[string]string
+
+	cpuThrottlingRateMu sync.RWMutex
+	cpuThrottlingRate   float64
+
+	metricsMu      sync.Mutex
+	metricsEnabled bool
 
 	backgroundPage bool
 
@@ -73,7 +95,10 @@ type Page struct {
 	// TODO: FrameSession changes by attachFrameSession (mutex?)
 	frameSessions map[cdp.FrameID]*FrameSession
 	workers       map[target.SessionID]*Worker
-	routes        []api.Route
+	routesMu      sync.RWMutex
+	routes        []*routeHandler
+	harRoutersMu  sync.RWMutex
+	harRouters    []*harRouter
 	vu            k6modules.VU
 
 	logger *log.Logger
@@ -90,25 +115,27 @@ func NewPage(
 	logger *log.Logger,
 ) (*Page, error) {
 	p := Page{
-		BaseEventEmitter: NewBaseEventEmitter(ctx),
-		ctx:              ctx,
-		session:          s,
-		browserCtx:       bctx,
-		targetID:         tid,
-		opener:           opener,
-		backgroundPage:   bp,
-		mediaType:        MediaTypeScreen,
-		colorScheme:      bctx.opts.ColorScheme,
-		reducedMotion:    bctx.opts.ReducedMotion,
-		extraHTTPHeaders: bctx.opts.ExtraHTTPHeaders,
-		timeoutSettings:  NewTimeoutSettings(bctx.timeoutSettings),
-		Keyboard:         NewKeyboard(ctx, s),
-		jsEnabled:        true,
-		frameSessions:    make(map[cdp.FrameID]*FrameSession),
-		workers:          make(map[target.SessionID]*Worker),
-		routes:           make([]api.Route, 0),
-		vu:               k6ext.GetVU(ctx),
-		logger:           logger,
+		BaseEventEmitter:  NewBaseEventEmitter(ctx),
+		ctx:               ctx,
+		session:           s,
+		browserCtx:        bctx,
+		targetID:          tid,
+		opener:            opener,
+		backgroundPage:    bp,
+		mediaType:         MediaTypeScreen,
+		colorScheme:       bctx.opts.ColorScheme,
+		reducedMotion:     bctx.opts.ReducedMotion,
+		networkConditions: bctx.opts.NetworkConditions,
+		cpuThrottlingRate: bctx.opts.CPUThrottlingRate,
+		extraHTTPHeaders:  bctx.opts.ExtraHTTPHeaders,
+		timeoutSettings:   NewTimeoutSettings(bctx.timeoutSettings),
+		Keyboard:          NewKeyboard(ctx, s),
+		jsEnabled:         true,
+		frameSessions:     make(map[cdp.FrameID]*FrameSession),
+		workers:           make(map[target.SessionID]*Worker),
+		routes:            make([]*routeHandler, 0),
+		vu:                k6ext.GetVU(ctx),
+		logger:            logger,
 	}
 
 	p.logger.Debugf("Page:NewPage", "sid:%v tid:%v backgroundPage:%t",
@@ -132,6 +159,7 @@ func NewPage(
 	p.frameSessions[cdp.FrameID(tid)] = p.mainFrameSession
 	p.Mouse = NewMouse(ctx, s, p.frameManager.MainFrame(), bctx.timeoutSettings, p.Keyboard)
 	p.Touchscreen = NewTouchscreen(ctx, s, p.Keyboard)
+	p.IndexedDB = NewIndexedDB(ctx, s, p.frameManager, p.logger)
 
 	action := target.SetAutoAttach(true, true).WithFlatten(true)
 	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
@@ -147,6 +175,10 @@ func NewPage(
 		return nil, fmt.Errorf("internal error while applying init scripts to page: %w", err)
 	}
 
+	if interval := bctx.opts.MetricsInterval; interval > 0 {
+		go p.sampleMetrics(interval)
+	}
+
 	return &p, nil
 }
 
@@ -285,9 +317,28 @@ func (p *Page) getFrameSession(frameID cdp.FrameID) *FrameSession {
 }
 
 func (p *Page) hasRoutes() bool {
+	p.routesMu.RLock()
+	defer p.routesMu.RUnlock()
+
 	return len(p.routes) > 0
 }
 
+// routesFor returns the route handlers that might match the URL,
+// the most recently registered first.
+func (p *Page) routesFor(url string) []*routeHandler {
+	p.routesMu.RLock()
+	defer p.routesMu.RUnlock()
+
+	var routes []*routeHandler
+	for i := len(p.routes) - 1; i >= 0; i-- {
+		if p.routes[i].mightMatch(url) {
+			routes = append(routes, p.routes[i])
+		}
+	}
+
+	return routes
+}
+
 func (p *Page) resetViewport() error {
 	p.logger.Debugf("Page:resetViewport", "sid:%v", p.sessionID())
 
@@ -360,6 +411,17 @@ func (p *Page) updateHttpCredentials() {
 	}
 }
 
+func (p *Page) updateRequestInterception() error {
+	p.logger.Debugf("Page:updateRequestInterception", "sid:%v", p.sessionID())
+
+	for _, fs := range p.frameSessions {
+		if err := fs.updateRequestInterception(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p *Page) viewportSize() Size {
 	return Size{
 		Width:  float64(p.emulatedSize.Viewport.Width),
@@ -503,6 +565,28 @@ func (p *Page) EmulateMedia(opts goja.Value) {
 	applySlowMo(p.ctx)
 }
 
+// EmulateNetworkConditions emulates the network conditions given as an object
+// or as a preset name such as "Slow 3G", "Fast 3G" or "4G".
+// Passing null turns off the emulation.
+func (p *Page) EmulateNetworkConditions(conditions goja.Value) {
+	p.logger.Debugf("Page:EmulateNetworkConditions", "sid:%v", p.sessionID())
+
+	var parsed *NetworkConditions
+	if gojaValueExists(conditions) {
+		parsed = NewNetworkConditions()
+		if err := parsed.Parse(p.ctx, conditions); err != nil {
+			k6ext.Panic(p.ctx, "parsing network conditions: %w", err)
+		}
+	}
+
+	p.networkConditions = parsed
+	for _, fs := range p.frameSessions {
+		fs.updateNetworkConditions(false)
+	}
+
+	applySlowMo(p.ctx)
+}
+
 // EmulateVisionDeficiency activates/deactivates emulation of a vision deficiency.
 func (p *Page) EmulateVisionDeficiency(typ string) {
 	p.logger.Debugf("Page:EmulateVisionDeficiency", "sid:%v typ:%s", p.sessionID(), typ)
@@ -586,6 +670,11 @@ func (p *Page) GetAttribute(selector string, name string, opts goja.Value) goja.
 	return p.MainFrame().GetAttribute(selector, name, opts)
 }
 
+// GetIndexedDB returns the IndexedDB databases of the origin of the page.
+func (p *Page) GetIndexedDB() api.IndexedDB {
+	return p.IndexedDB
+}
+
 // GetKeyboard returns the keyboard for the page.
 func (p *Page) GetKeyboard() api.Keyboard {
 	return p.Keyboard
@@ -703,6 +792,22 @@ func (p *Page) MainFrame() api.Frame {
 	return mf
 }
 
+// On calls the handler with the data of each given page event.
+// The "websocket" event is called with the WebSocket opened by the page,
+// and the "eventsource" event with each EventSourceMessage received.
+// The handler is called on the event loop until the page is closed
+// or the iteration ends.
+func (p *Page) On(event string, handler func(any) error) error {
+	switch event {
+	case EventPageEventSource, EventPageWebSocket:
+	default:
+		return fmt.Errorf("unknown page event: %q", event)
+	}
+	handleEvents(p.ctx, p.session.Done(), p, []string{event}, handler, p.logger)
+
+	return nil
+}
+
 // Opener returns the opener of the target.
 func (p *Page) Opener() api.Page {
 	return p.opener
@@ -740,12 +845,12 @@ func (p *Page) QueryAll(selector string) ([]api.ElementHandle, error) {
 }
 
 // Reload will reload the current page.
-func (p *Page) Reload(opts goja.Value) api.Response {
+func (p *Page) Reload(opts goja.Value) (api.Response, error) {
 	p.logger.Debugf("Page:Reload", "sid:%v", p.sessionID())
 
 	parsedOpts := NewPageReloadOptions(LifecycleEventLoad, p.defaultTimeout())
 	if err := parsedOpts.Parse(p.ctx, opts); err != nil {
-		k6ext.Panic(p.ctx, "parsing reload options: %w", err)
+		return nil, fmt.Errorf("parsing reload options: %w", err)
 	}
 
 	timeoutCtx, timeoutCancelFn := context.WithTimeout(p.ctx, parsedOpts.Timeout)
@@ -771,7 +876,7 @@ func (p *Page) Reload(opts goja.Value) api.Response {
 
 	action := cdppage.Reload()
 	if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
-		k6ext.Panic(p.ctx, "reloading page: %w", err)
+		return nil, fmt.Errorf("reloading page: %w", err)
 	}
 
 	wrapTimeoutError := func(err error) error {
@@ -790,8 +895,9 @@ func (p *Page) Reload(opts goja.Value) api.Response {
 	var event *NavigationEvent
 	select {
 	case <-p.ctx.Done():
+		return nil, fmt.Errorf("reloading page: %w", p.ctx.Err())
 	case <-timeoutCtx.Done():
-		k6ext.Panic(p.ctx, "%w", wrapTimeoutError(timeoutCtx.Err()))
+		return nil, wrapTimeoutError(timeoutCtx.Err())
 	case data := <-ch:
 		event = data.(*NavigationEvent)
 	}
@@ -807,17 +913,85 @@ func (p *Page) Reload(opts goja.Value) api.Response {
 	select {
 	case <-lifecycleEvtCh:
 	case <-timeoutCtx.Done():
-		k6ext.Panic(p.ctx, "%w", wrapTimeoutError(timeoutCtx.Err()))
+		return nil, wrapTimeoutError(timeoutCtx.Err())
 	}
 
 	applySlowMo(p.ctx)
 
-	return resp
+	// Since response will be in an interface, it will never be nil,
+	// so we need to return nil explicitly.
+	if resp == nil {
+		return nil, nil
+	}
+
+	return resp, nil
+}
+
+// Route registers a handler that intercepts the requests
+// whose URL matches the given glob, regular expression or predicate.
+func (p *Page) Route(url goja.Value, handler api.RouteHandler) {
+	p.logger.Debugf("Page:Route", "sid:%v url:%v", p.sessionID(), url)
+
+	rh, err := newRouteHandler(p.ctx, p.session.Done(), url, handler)
+	if err != nil {
+		k6ext.Panic(p.ctx, "adding route: %w", err)
+	}
+
+	p.routesMu.Lock()
+	p.routes = append(p.routes, rh)
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "adding route: %w", err)
+	}
 }
 
-// Route is not implemented.
-func (p *Page) Route(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(p.ctx, "Page.route(url, handler) has not been implemented yet")
+// addHARRouter adds a HAR router that records the requests of this page.
+// Its HAR file is updated when the browser context is closed.
+func (p *Page) addHARRouter(r *harRouter) {
+	if !r.opts.Update {
+		return
+	}
+
+	p.harRoutersMu.Lock()
+	p.harRouters = append(p.harRouters, r)
+	p.harRoutersMu.Unlock()
+
+	p.browserCtx.updateHAROnClose(r.har)
+}
+
+// recordHAR records a finished, failed or redirected request
+// in the HAR files of this page and of its browser context.
+func (p *Page) recordHAR(req *Request, end time.Time) {
+	p.harRoutersMu.RLock()
+	for _, r := range p.harRouters {
+		r.record(p, req, end)
+	}
+	p.harRoutersMu.RUnlock()
+
+	if p.browserCtx != nil {
+		p.browserCtx.recordHAR(p, req, end)
+	}
+}
+
+// RouteFromHAR answers the requests of the page whose URL
+// matches the url option from the HAR file at path.
+func (p *Page) RouteFromHAR(path string, opts goja.Value) {
+	p.logger.Debugf("Page:RouteFromHAR", "sid:%v path:%q", p.sessionID(), path)
+
+	rh, router, err := newHARRouteHandler(p.ctx, path, opts)
+	if err != nil {
+		k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+	}
+	p.addHARRouter(router)
+
+	p.routesMu.Lock()
+	p.routes = append(p.routes, rh)
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "routing from HAR: %w", err)
+	}
 }
 
 // Screenshot will instruct Chrome to save a screenshot of the current page and save it to specified file.
@@ -841,10 +1015,10 @@ func (p *Page) SelectOption(selector string, values goja.Value, opts goja.Value)
 	return p.MainFrame().SelectOption(selector, values, opts)
 }
 
-func (p *Page) SetContent(html string, opts goja.Value) {
+func (p *Page) SetContent(html string, opts goja.Value) error {
 	p.logger.Debugf("Page:SetContent", "sid:%v", p.sessionID())
 
-	p.MainFrame().SetContent(html, opts)
+	return p.MainFrame().SetContent(html, opts)
 }
 
 // SetDefaultNavigationTimeout sets the default navigation timeout in milliseconds.
@@ -901,6 +1075,37 @@ func (p *Page) TextContent(selector string, opts goja.Value) string {
 	return p.MainFrame().TextContent(selector, opts)
 }
 
+// ThrottleCPU slows down the CPU of the page by the given rate,
+// where 1 is no throttling and 2 is a 2x slowdown.
+func (p *Page) ThrottleCPU(rate float64) {
+	p.logger.Debugf("Page:ThrottleCPU", "sid:%v rate:%.2f", p.sessionID(), rate)
+
+	if rate < 1 {
+		k6ext.Panic(p.ctx, `invalid CPU throttling rate "%.2f": precondition 1 <= RATE failed`, rate)
+	}
+
+	p.cpuThrottlingRateMu.Lock()
+	p.cpuThrottlingRate = rate
+	p.cpuThrottlingRateMu.Unlock()
+
+	for _, fs := range p.frameSessions {
+		if err := fs.updateCPUThrottlingRate(false); err != nil {
+			k6ext.Panic(p.ctx, "throttling CPU: %w", err)
+		}
+	}
+
+	applySlowMo(p.ctx)
+}
+
+// getCPUThrottlingRate returns the CPU throttling rate of the page,
+// or zero if the CPU throttling was never set.
+func (p *Page) getCPUThrottlingRate() float64 {
+	p.cpuThrottlingRateMu.RLock()
+	defer p.cpuThrottlingRateMu.RUnlock()
+
+	return p.cpuThrottlingRate
+}
+
 func (p *Page) Title() string {
 	p.logger.Debugf("Page:Title", "sid:%v", p.sessionID())
 
@@ -914,9 +1119,26 @@ func (p *Page) Type(selector string, text string, opts goja.Value) {
 	p.MainFrame().Type(selector, text, opts)
 }
 
-// Unroute is not implemented.
-func (p *Page) Unroute(url goja.Value, handler goja.Callable) {
-	k6ext.Panic(p.ctx, "Page.unroute(url, handler) has not been implemented yet")
+// Unroute removes the route handlers registered for the URL.
+// If a handler is given, only that handler is removed.
+func (p *Page) Unroute(url goja.Value, handler goja.Value) {
+	p.logger.Debugf("Page:Unroute", "sid:%v url:%v", p.sessionID(), url)
+
+	p.routesMu.Lock()
+	routes := make([]*routeHandler, 0, len(p.routes))
+	for _, rh := range p.routes {
+		if rh.equals(url, handler) {
+			rh.close()
+			continue
+		}
+		routes = append(routes, rh)
+	}
+	p.routes = routes
+	p.routesMu.Unlock()
+
+	if err := p.updateRequestInterception(); err != nil {
+		k6ext.Panic(p.ctx, "removing route: %w", err)
+	}
 }
 
 // URL returns the location of the page.
@@ -971,16 +1193,162 @@ func (p *Page) WaitForNavigation(opts goja.Value) (api.Response, error) {
 	return p.frameManager.MainFrame().WaitForNavigation(opts)
 }
 
-// WaitForRequest is not implemented.
-func (p *Page) WaitForRequest(urlOrPredicate, opts goja.Value) api.Request {
-	k6ext.Panic(p.ctx, "Page.waitForRequest(urlOrPredicate, opts) has not been implemented yet")
-	return nil
+// WaitForRequest starts waiting for a request whose URL matches the given URL
+// string, glob or regular expression, or for which the given predicate returns
+// true. The returned function waits for the request. Since the page starts
+// listening to the requests before WaitForRequest returns, the requests made
+// before the returned function is called are not missed.
+func (p *Page) WaitForRequest(matcher api.RequestMatcher, opts goja.Value) (func() (api.Request, error), error) {
+	p.logger.Debugf("Page:WaitForRequest", "sid:%v", p.sessionID())
+
+	var predicate func(data any) (bool, error)
+	if matcher.Predicate != nil {
+		predicate = func(data any) (bool, error) {
+			return matcher.Predicate(data.(*Request)) //nolint:forcetypeassert
+		}
+	}
+	wait, err := p.waitForNetworkEvent(EventPageRequest, matcher.URL, predicate, opts)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for request: %w", err)
+	}
+
+	return func() (api.Request, error) {
+		data, err := wait()
+		if err != nil {
+			return nil, fmt.Errorf("waiting for request: %w", err)
+		}
+		return data.(*Request), nil //nolint:forcetypeassert
+	}, nil
 }
 
-// WaitForResponse is not implemented.
-func (p *Page) WaitForResponse(urlOrPredicate, opts goja.Value) api.Response {
-	k6ext.Panic(p.ctx, "Page.waitForResponse(urlOrPredicate, opts) has not been implemented yet")
-	return nil
+// WaitForResponse starts waiting for a response whose URL matches the given URL
+// string, glob or regular expression, or for which the given predicate returns
+// true. The returned function waits for the response, the same way as the one
+// returned by WaitForRequest.
+func (p *Page) WaitForResponse(matcher api.ResponseMatcher, opts goja.Value) (func() (api.Response, error), error) {
+	p.logger.Debugf("Page:WaitForResponse", "sid:%v", p.sessionID())
+
+	var predicate func(data any) (bool, error)
+	if matcher.Predicate != nil {
+		predicate = func(data any) (bool, error) {
+			return matcher.Predicate(data.(*Response)) //nolint:forcetypeassert
+		}
+	}
+	wait, err := p.waitForNetworkEvent(EventPageResponse, matcher.URL, predicate, opts)
+	if err != nil {
+		return nil, fmt.Errorf("waiting for response: %w", err)
+	}
+
+	return func() (api.Response, error) {
+		data, err := wait()
+		if err != nil {
+			return nil, fmt.Errorf("waiting for response: %w", err)
+		}
+		return data.(*Response), nil //nolint:forcetypeassert
+	}, nil
+}
+
+// waitForNetworkEvent starts listening to the request or the response events
+// of the page, and returns a function that waits for the first one that matches
+// the URL, or the predicate if it's not nil. The predicate is called on the
+// event loop, so waitForNetworkEvent must be called on the event loop too.
+func (p *Page) waitForNetworkEvent(
+	event string, url goja.Value, predicate func(data any) (bool, error), opts goja.Value,
+) (func() (any, error), error) {
+	parsedOpts := NewPageWaitForNetworkEventOptions(p.defaultTimeout())
+	if err := parsedOpts.Parse(p.ctx, opts); err != nil {
+		return nil, fmt.Errorf("parsing options: %w", err)
+	}
+
+	var urlMatches urlMatcher
+	if predicate == nil {
+		m, err := newURLMatcher(p.vu.Runtime(), url)
+		if err != nil {
+			return nil, err
+		}
+		urlMatches = m
+	}
+
+	var (
+		timeoutCtx, timeoutCancel = context.WithTimeout(p.ctx, parsedOpts.Timeout)
+
+		chEvHandler = make(chan Event)
+		result      = make(chan any, 1)
+		failed      = make(chan error, 1)
+		// matched is only accessed by the event handler goroutine below,
+		// or on the event loop if there is a predicate.
+		matched bool
+		match   func(data any)
+	)
+	if predicate == nil {
+		match = func(data any) {
+			if matched {
+				return
+			}
+			var u string
+			switch d := data.(type) {
+			case *Request:
+				u = d.URL()
+			case *Response:
+				u = d.URL()
+			}
+			if urlMatches(u) {
+				matched = true
+				result <- data
+			}
+		}
+	} else {
+		queue := k6ext.NewTaskQueue(p.vu)
+		go func() {
+			<-timeoutCtx.Done()
+			queue.Close()
+		}()
+		match = func(data any) {
+			queue.Queue(func() error {
+				if matched {
+					return nil
+				}
+				ok, err := predicate(data)
+				switch {
+				case err != nil:
+					matched = true
+					failed <- fmt.Errorf("calling predicate: %w", err)
+				case ok:
+					matched = true
+					result <- data
+				}
+				return nil
+			})
+		}
+	}
+
+	p.on(timeoutCtx, []string{event}, chEvHandler)
+	go func() {
+		for {
+			select {
+			case <-timeoutCtx.Done():
+				return
+			case ev := <-chEvHandler:
+				match(ev.data)
+			}
+		}
+	}()
+
+	return func() (any, error) {
+		defer timeoutCancel()
+
+		select {
+		case data := <-result:
+			return data, nil
+		case err := <-failed:
+			return nil, err
+		case <-timeoutCtx.Done():
+			return nil, &k6ext.UserFriendlyError{
+				Err:     timeoutCtx.Err(),
+				Timeout: parsedOpts.Timeout,
+			}
+		}
+	}, nil
 }
 
 // WaitForSelector waits for the given selector to match the waiting criteria.
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/performance"
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+// Metrics returns the renderer metrics of the page, such as JSHeapUsedSize,
+// Nodes, LayoutCount, RecalcStyleDuration, ScriptDuration and TaskDuration.
+// Durations are in seconds, and sizes are in bytes.
+func (p *Page) Metrics() (map[string]float64, error) {
+	p.logger.Debugf("Page:Metrics", "sid:%v", p.sessionID())
+
+	metrics, err := p.metrics()
+	if err != nil {
+		return nil, fmt.Errorf("getting page metrics: %w", err)
+	}
+
+	return metrics, nil
+}
+
+// metrics returns the renderer metrics of the page, and
+// enables the performance domain the first time it is called.
+func (p *Page) metrics() (map[string]float64, error) {
+	p.metricsMu.Lock()
+	if !p.metricsEnabled {
+		if err := performance.Enable().Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+			p.metricsMu.Unlock()
+			return nil, fmt.Errorf("enabling performance metrics: %w", err)
+		}
+		p.metricsEnabled = true
+	}
+	p.metricsMu.Unlock()
+
+	mm, err := performance.GetMetrics().Do(cdp.WithExecutor(p.ctx, p.session))
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	metrics := make(map[string]float64, len(mm))
+	for _, m := range mm {
+		metrics[m.Name] = m.Value
+	}
+
+	return metrics, nil
+}
+
+// sampleMetrics periodically pushes the renderer metrics
+// of the page to k6 until the page is closed.
+func (p *Page) sampleMetrics(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.session.Done():
+			return
+		case <-ticker.C:
+			metrics, err := p.metrics()
+			if err != nil {
+				p.logger.Debugf("Page:sampleMetrics", "sid:%v err:%v", p.sessionID(), err)
+				continue
+			}
+			p.pushMetrics(metrics)
+		}
+	}
+}
+
+// pushMetrics pushes the renderer metrics that have a k6 metric.
+// Durations are converted to milliseconds.
+func (p *Page) pushMetrics(metrics map[string]float64) {
+	k6m := k6ext.GetCustomMetrics(p.ctx)
+	if k6m == nil {
+		return
+	}
+
+	var (
+		state = p.vu.State()
+		// The page tag tells apart the samples of the pages of a VU.
+		tags    = state.Tags.GetCurrentValues().Tags.With("page", p.targetID.String())
+		now     = time.Now()
+		samples []k6metrics.Sample
+	)
+	// The main frame might be missing if the page is being closed.
+	if f := p.frameManager.MainFrame(); f != nil && state.Options.SystemTags.Has(k6metrics.TagURL) {
+		tags = tags.With("url", f.URL())
+	}
+	for name, value := range metrics {
+		metric, ok := k6m.PageMetrics[name]
+		if !ok {
+			continue
+		}
+		if metric.Contains == k6metrics.Time {
+			value *= float64(time.Second / time.Millisecond)
+		}
+		samples = append(samples, k6metrics.Sample{
+			TimeSeries: k6metrics.TimeSeries{Metric: metric, Tags: tags},
+			Value:      value,
+			Time:       now,
+		})
+	}
+	if len(samples) == 0 {
+		return
+	}
+	k6metrics.PushIfNotDone(p.ctx, state.Samples, k6metrics.ConnectedSamples{Samples: samples})
+}
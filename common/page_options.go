@@ -23,6 +23,12 @@ type PageReloadOptions struct {
 	Timeout   time.Duration  `json:"timeout"`
 }
 
+// PageWaitForNetworkEventOptions are the options
+// for Page.waitForRequest and Page.waitForResponse.
+type PageWaitForNetworkEventOptions struct {
+	Timeout time.Duration `json:"timeout"`
+}
+
 type PageScreenshotOptions struct {
 	Clip           *page.Viewport `json:"clip"`
 	Path           string         `json:"path"`
@@ -141,3 +147,25 @@ func (o *PageScreenshotOptions) Parse(ctx context.Context, opts goja.Value) erro
 
 	return nil
 }
+
+// NewPageWaitForNetworkEventOptions returns a new PageWaitForNetworkEventOptions.
+func NewPageWaitForNetworkEventOptions(defaultTimeout time.Duration) *PageWaitForNetworkEventOptions {
+	return &PageWaitForNetworkEventOptions{
+		Timeout: defaultTimeout,
+	}
+}
+
+// Parse parses the wait for network event options.
+func (o *PageWaitForNetworkEventOptions) Parse(ctx context.Context, opts goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if opts != nil && !goja.IsUndefined(opts) && !goja.IsNull(opts) {
+		opts := opts.ToObject(rt)
+		for _, k := range opts.Keys() {
+			switch k {
+			case "timeout":
+				o.Timeout = time.Duration(opts.Get(k).ToInteger()) * time.Millisecond
+			}
+		}
+	}
+	return nil
+}
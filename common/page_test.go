@@ -4,8 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	k6metrics "go.k6.io/k6/metrics"
 )
 
 // TestPageLocator can be removed later on when we add integration
@@ -32,3 +37,48 @@ func TestPageLocator(t *testing.T) {
 
 	// other behavior will be tested via integration tests
 }
+
+func TestPagePushMetrics(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	vu.MoveToVUContext()
+	ctx := k6ext.WithCustomMetrics(vu.Context(), k6ext.RegisterCustomMetrics(k6metrics.NewRegistry()))
+	p := &Page{
+		ctx:      ctx,
+		vu:       vu,
+		targetID: "target",
+		frameManager: &FrameManager{
+			ctx:       ctx,
+			mainFrame: &Frame{id: "1", ctx: ctx, url: "http://host.com/"},
+		},
+	}
+
+	p.pushMetrics(map[string]float64{
+		"JSHeapUsedSize": 1024,
+		"ScriptDuration": 0.25,
+		"Unknown":        1,
+	})
+
+	values := make(map[string]float64)
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		values[s.Metric.Name] = s.Value
+		url, _ := s.Tags.Get("url")
+		assert.Equal(t, "http://host.com/", url)
+		page, _ := s.Tags.Get("page")
+		assert.Equal(t, "target", page)
+	})
+	assert.Equal(t, map[string]float64{
+		"browser_page_js_heap_used_size": 1024,
+		"browser_page_script_duration":   250, // converted to milliseconds
+	}, values)
+
+	// The samples are pushed without the url tag if there is no main frame.
+	p.frameManager.mainFrame = nil
+	p.pushMetrics(map[string]float64{"JSHeapUsedSize": 1024})
+	n := vu.AssertSamples(func(s k6metrics.Sample) {
+		_, ok := s.Tags.Get("url")
+		assert.False(t, ok)
+	})
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,101 @@
+package common
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+// processMetricsInterval is how often the process tree
+// of a local browser is sampled.
+const processMetricsInterval = time.Second
+
+// errProcessMetricsUnsupported is returned when the process
+// metrics can't be sampled on the current operating system.
+var errProcessMetricsUnsupported = errors.New("process metrics are not supported on this platform")
+
+// processStats are the resource usage of a process tree.
+type processStats struct {
+	// cpuTime is the total CPU time used by the processes
+	// of the tree, including their reaped children.
+	cpuTime time.Duration
+	// rss is the sum of the resident set sizes of
+	// the processes of the tree, in bytes.
+	rss uint64
+}
+
+// sampleProcessMetrics periodically pushes the CPU usage and the memory
+// RSS of the browser process tree to k6 until the browser is closed.
+func (b *Browser) sampleProcessMetrics(interval time.Duration) {
+	pid := b.browserProc.Pid()
+	prev, err := processTreeStats(pid)
+	if err != nil {
+		b.logger.Debugf("Browser:sampleProcessMetrics", "pid:%d err:%v", pid, err)
+		return
+	}
+	prevTime := time.Now()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case <-b.browserProc.processDone:
+			return
+		case now := <-ticker.C:
+			stats, err := processTreeStats(pid)
+			if err != nil {
+				b.logger.Debugf("Browser:sampleProcessMetrics", "pid:%d err:%v", pid, err)
+				continue
+			}
+			b.pushProcessMetrics(now, cpuUsage(prev, stats, now.Sub(prevTime)), stats.rss)
+			prev, prevTime = stats, now
+		}
+	}
+}
+
+// cpuUsage returns the CPU usage between two samples of a process tree
+// as a percentage of a single core. It can be more than 100 when the
+// processes run on multiple cores.
+func cpuUsage(prev, cur processStats, elapsed time.Duration) float64 {
+	if elapsed <= 0 || cur.cpuTime < prev.cpuTime {
+		return 0
+	}
+
+	return float64(cur.cpuTime-prev.cpuTime) / float64(elapsed) * 100
+}
+
+// pushProcessMetrics pushes the process metrics of the browser
+// tagged with the VU that launched it.
+func (b *Browser) pushProcessMetrics(now time.Time, cpu float64, rss uint64) {
+	k6m := k6ext.GetCustomMetrics(b.ctx)
+	if k6m == nil {
+		return
+	}
+
+	state := b.vu.State()
+	if state == nil {
+		return
+	}
+	tags := state.Tags.GetCurrentValues().Tags.With("vu", strconv.FormatUint(state.VUID, 10))
+	k6metrics.PushIfNotDone(b.ctx, state.Samples, k6metrics.ConnectedSamples{
+		Samples: []k6metrics.Sample{
+			{
+				TimeSeries: k6metrics.TimeSeries{Metric: k6m.ProcessCPU, Tags: tags},
+				Value:      cpu,
+				Time:       now,
+			},
+			{
+				TimeSeries: k6metrics.TimeSeries{Metric: k6m.ProcessMemoryRSS, Tags: tags},
+				Value:      float64(rss),
+				Time:       now,
+			},
+		},
+	})
+}
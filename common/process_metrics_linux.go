@@ -0,0 +1,217 @@
+//go:build linux
+// +build linux
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userHZ is the number of clock ticks per second used by the
+// CPU times in /proc, which is fixed at 100 for user space.
+const userHZ = 100
+
+// procStat is the part of /proc/<pid>/stat used for the process metrics.
+type procStat struct {
+	pid  int
+	ppid int
+	// CPU times in clock ticks. The children times
+	// are of the reaped children of the process.
+	utime, stime, cutime, cstime uint64
+	// rss is the resident set size in pages.
+	rss uint64
+}
+
+// processTreeStats returns the resource usage of the
+// process with the pid and all of its descendants.
+func processTreeStats(pid int) (processStats, error) {
+	tree, err := readProcTree("/proc", pid)
+	if err != nil {
+		return processStats{}, err
+	}
+
+	var ticks, pages uint64
+	for _, s := range tree {
+		ticks += s.utime + s.stime + s.cutime + s.cstime
+		pages += s.rss
+	}
+
+	return processStats{
+		cpuTime: time.Duration(ticks) * time.Second / userHZ,
+		rss:     pages * uint64(os.Getpagesize()),
+	}, nil
+}
+
+// readProcTree returns the stats of the process with the pid and all of its
+// descendants. The descendants are found by walking the children files of
+// the tasks of the processes. These files are only available if the kernel
+// is built with CONFIG_PROC_CHILDREN, otherwise all of the processes are
+// read to find the descendants.
+func readProcTree(procDir string, pid int) ([]procStat, error) {
+	root, err := readProcStat(procDir, pid)
+	if err != nil {
+		return nil, fmt.Errorf("process %d is not running: %w", pid, err)
+	}
+	taskChildren := filepath.Join(procDir, strconv.Itoa(pid), "task", strconv.Itoa(pid), "children")
+	if _, err := os.Stat(taskChildren); err != nil {
+		return scanProcTree(procDir, root)
+	}
+
+	tree := []procStat{root}
+	for i := 0; i < len(tree); i++ {
+		for _, cpid := range readChildren(procDir, tree[i].pid) {
+			s, err := readProcStat(procDir, cpid)
+			if err != nil {
+				// the process exited or its stat can't be parsed.
+				continue
+			}
+			tree = append(tree, s)
+		}
+	}
+
+	return tree, nil
+}
+
+// readChildren returns the pids of the children of the tasks of the process.
+func readChildren(procDir string, pid int) []int {
+	files, _ := filepath.Glob(filepath.Join(procDir, strconv.Itoa(pid), "task", "*", "children"))
+
+	var pids []int
+	for _, f := range files {
+		buf, err := os.ReadFile(f)
+		if err != nil {
+			// the task exited after listing the directory.
+			continue
+		}
+		for _, field := range strings.Fields(string(buf)) {
+			if cpid, err := strconv.Atoi(field); err == nil {
+				pids = append(pids, cpid)
+			}
+		}
+	}
+
+	return pids
+}
+
+// scanProcTree returns the stats of the root process and all of its
+// descendants by reading the stats of all of the running processes.
+func scanProcTree(procDir string, root procStat) ([]procStat, error) {
+	stats, err := readProcStats(procDir)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]int, len(stats))
+	for _, s := range stats {
+		children[s.ppid] = append(children[s.ppid], s.pid)
+	}
+
+	tree := []procStat{root}
+	for i := 0; i < len(tree); i++ {
+		for _, cpid := range children[tree[i].pid] {
+			tree = append(tree, stats[cpid])
+		}
+	}
+
+	return tree, nil
+}
+
+// readProcStats returns the stats of the running processes keyed by their pids.
+func readProcStats(procDir string) (map[int]procStat, error) {
+	entries, err := os.ReadDir(procDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", procDir, err)
+	}
+
+	stats := make(map[int]procStat, len(entries))
+	for _, e := range entries {
+		pid, err := strconv.Atoi(e.Name())
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		s, err := readProcStat(procDir, pid)
+		if err != nil {
+			// the process exited after listing the
+			// directory or its stat can't be parsed.
+			continue
+		}
+		stats[s.pid] = s
+	}
+
+	return stats, nil
+}
+
+// readProcStat reads and parses the stat of the process with the pid.
+func readProcStat(procDir string, pid int) (procStat, error) {
+	buf, err := os.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, fmt.Errorf("%w", err)
+	}
+	s, err := parseProcStat(string(buf))
+	if err != nil {
+		return procStat{}, fmt.Errorf("parsing stat of process %d: %w", pid, err)
+	}
+
+	return s, nil
+}
+
+// parseProcStat parses the contents of a /proc/<pid>/stat file.
+// See proc(5) for the fields.
+func parseProcStat(stat string) (procStat, error) {
+	// The command name is in parentheses and can contain
+	// spaces and parentheses, so the fields after it are
+	// split after the last closing parenthesis.
+	open, closing := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || closing < open {
+		return procStat{}, errors.New("missing command name")
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(stat[:open]))
+	if err != nil {
+		return procStat{}, fmt.Errorf("parsing pid: %w", err)
+	}
+
+	// fields[0] is the 3rd field of the file, the process state.
+	const (
+		ppidField   = 4 - 3
+		utimeField  = 14 - 3
+		rssField    = 24 - 3
+		fieldsCount = rssField + 1
+	)
+	fields := strings.Fields(stat[closing+1:])
+	if len(fields) < fieldsCount {
+		return procStat{}, fmt.Errorf("expected at least %d fields, got %d", fieldsCount+2, len(fields)+2)
+	}
+	ppid, err := strconv.Atoi(fields[ppidField])
+	if err != nil {
+		return procStat{}, fmt.Errorf("parsing ppid: %w", err)
+	}
+	var times [4]uint64
+	for i := range times {
+		if times[i], err = strconv.ParseUint(fields[utimeField+i], 10, 64); err != nil {
+			return procStat{}, fmt.Errorf("parsing CPU times: %w", err)
+		}
+	}
+	// rss is signed in the kernel.
+	rss, err := strconv.ParseInt(fields[rssField], 10, 64)
+	if err != nil {
+		return procStat{}, fmt.Errorf("parsing rss: %w", err)
+	}
+	if rss < 0 {
+		rss = 0
+	}
+
+	return procStat{
+		pid:    pid,
+		ppid:   ppid,
+		utime:  times[0],
+		stime:  times[1],
+		cutime: times[2],
+		cstime: times[3],
+		rss:    uint64(rss),
+	}, nil
+}
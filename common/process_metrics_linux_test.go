@@ -0,0 +1,116 @@
+//go:build linux
+// +build linux
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcStat(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		stat := "4242 (chrome (renderer) x) S 4200 4200 4200 0 -1 4194560 " +
+			"1000 0 0 0 150 50 7 3 20 0 12 0 200 1000000 2048 " +
+			"18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0\n"
+
+		s, err := parseProcStat(stat)
+		require.NoError(t, err)
+		assert.Equal(t, procStat{
+			pid:    4242,
+			ppid:   4200,
+			utime:  150,
+			stime:  50,
+			cutime: 7,
+			cstime: 3,
+			rss:    2048,
+		}, s)
+	})
+
+	t.Run("err", func(t *testing.T) {
+		t.Parallel()
+
+		for _, stat := range []string{
+			"",
+			"4242 chrome S 4200",
+			"4242 (chrome) S 4200 4200",
+			"4242 (chrome) S x 4200 4200 0 -1 4194560 1000 0 0 0 150 50 7 3 20 0 12 0 200 1000000 2048",
+		} {
+			_, err := parseProcStat(stat)
+			assert.Error(t, err, stat)
+		}
+	})
+}
+
+func TestProcessTreeStats(t *testing.T) {
+	t.Parallel()
+
+	stats, err := processTreeStats(os.Getpid())
+	require.NoError(t, err)
+	assert.Positive(t, stats.rss)
+
+	_, err = processTreeStats(-1)
+	assert.Error(t, err)
+}
+
+func TestReadProcTree(t *testing.T) {
+	t.Parallel()
+
+	// newProcDir returns a proc directory with a tree of 1 -> 2 -> 3,
+	// an unrelated process 4 and a child 5 of 1 with an unparsable stat.
+	newProcDir := func(t *testing.T, withChildren bool) string {
+		t.Helper()
+
+		dir := t.TempDir()
+		for _, p := range []struct{ pid, ppid int }{{1, 0}, {2, 1}, {3, 2}, {4, 0}, {5, 1}} {
+			task := filepath.Join(dir, strconv.Itoa(p.pid), "task", strconv.Itoa(p.pid))
+			require.NoError(t, os.MkdirAll(task, 0o700))
+
+			stat := fmt.Sprintf("%d (proc) S %d 0 0 0 -1 0 0 0 0 0 1 1 0 0 20 0 1 0 0 0 1\n", p.pid, p.ppid)
+			if p.pid == 5 {
+				stat = "5 (proc) S"
+			}
+			require.NoError(t, os.WriteFile(filepath.Join(dir, strconv.Itoa(p.pid), "stat"), []byte(stat), 0o600))
+		}
+		if withChildren {
+			for pid, children := range map[int]string{1: "2 5 ", 2: "3 ", 3: "", 4: "", 5: ""} {
+				f := filepath.Join(dir, strconv.Itoa(pid), "task", strconv.Itoa(pid), "children")
+				require.NoError(t, os.WriteFile(f, []byte(children), 0o600))
+			}
+		}
+
+		return dir
+	}
+
+	for name, withChildren := range map[string]bool{"children": true, "scan": false} {
+		withChildren := withChildren
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := newProcDir(t, withChildren)
+			tree, err := readProcTree(dir, 1)
+			require.NoError(t, err)
+
+			var pids []int
+			for _, s := range tree {
+				pids = append(pids, s.pid)
+			}
+			sort.Ints(pids)
+			assert.Equal(t, []int{1, 2, 3}, pids)
+
+			_, err = readProcTree(dir, 6)
+			assert.ErrorContains(t, err, "process 6 is not running")
+		})
+	}
+}
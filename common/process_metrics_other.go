@@ -0,0 +1,10 @@
+//go:build !linux
+// +build !linux
+
+package common
+
+// processTreeStats returns the resource usage of the
+// process with the pid and all of its descendants.
+func processTreeStats(pid int) (processStats, error) {
+	return processStats{}, errProcessMetricsUnsupported
+}
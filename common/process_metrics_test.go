@@ -0,0 +1,46 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+func TestCPUUsage(t *testing.T) {
+	t.Parallel()
+
+	prev := processStats{cpuTime: time.Second}
+
+	assert.Equal(t, 50.0, cpuUsage(prev, processStats{cpuTime: 2 * time.Second}, 2*time.Second))
+	assert.Equal(t, 150.0, cpuUsage(prev, processStats{cpuTime: 2500 * time.Millisecond}, time.Second))
+	assert.Zero(t, cpuUsage(prev, processStats{cpuTime: 0}, time.Second), "restarted process")
+	assert.Zero(t, cpuUsage(prev, processStats{cpuTime: 2 * time.Second}, 0), "no elapsed time")
+}
+
+func TestBrowserPushProcessMetrics(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	vu.MoveToVUContext()
+	ctx := k6ext.WithCustomMetrics(vu.Context(), k6ext.RegisterCustomMetrics(k6metrics.NewRegistry()))
+	b := &Browser{ctx: ctx, vu: vu}
+
+	b.pushProcessMetrics(time.Now(), 42, 1024)
+
+	values := make(map[string]float64)
+	vu.AssertSamples(func(s k6metrics.Sample) {
+		values[s.Metric.Name] = s.Value
+		_, ok := s.Tags.Get("vu")
+		assert.True(t, ok, "missing vu tag")
+	})
+	assert.Equal(t, map[string]float64{
+		"browser_process_cpu":        42,
+		"browser_process_memory_rss": 1024,
+	}, values)
+}
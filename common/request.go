@@ -2,6 +2,7 @@ package common
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -29,6 +30,9 @@ type Request struct {
 	responseMu          sync.RWMutex
 	response            *Response
 	redirectChain       []*Request
+	redirectedFrom      *Request
+	redirectMu          sync.RWMutex
+	redirectedTo        *Request
 	requestID           network.RequestID
 	documentID          string
 	url                 *url.URL
@@ -108,6 +112,11 @@ func NewRequest(ctx context.Context, rp NewRequestParams) (*Request, error) {
 		ctx:                 ctx,
 		vu:                  k6ext.GetVU(ctx),
 	}
+	// The last request of the redirect chain is the one
+	// that was redirected to this request.
+	if n := len(rp.redirectChain); n > 0 {
+		r.redirectedFrom = rp.redirectChain[n-1]
+	}
 	for n, v := range ev.Request.Headers {
 		if s, ok := v.(string); ok {
 			r.headers[n] = append(r.headers[n], s)
@@ -148,6 +157,13 @@ func (r *Request) setLoadedFromCache(fromMemoryCache bool) {
 	r.fromMemoryCache = fromMemoryCache
 }
 
+func (r *Request) setRedirectedTo(req *Request) {
+	r.redirectMu.Lock()
+	defer r.redirectMu.Unlock()
+
+	r.redirectedTo = req
+}
+
 func (r *Request) AllHeaders() map[string]string {
 	// TODO: fix this data to include "ExtraInfo" header data
 	headers := make(map[string]string)
@@ -157,9 +173,16 @@ func (r *Request) AllHeaders() map[string]string {
 	return headers
 }
 
+// Failure returns an object with the error text of the request
+// if the request failed, or null otherwise.
 func (r *Request) Failure() goja.Value {
-	k6ext.Panic(r.ctx, "Request.failure() has not been implemented yet")
-	return nil
+	if r.errorText == "" {
+		return goja.Null()
+	}
+	rt := r.vu.Runtime()
+	return rt.ToValue(map[string]string{
+		"errorText": r.errorText,
+	})
 }
 
 // Frame returns the frame within which the request was made.
@@ -218,19 +241,53 @@ func (r *Request) PostDataBuffer() goja.ArrayBuffer {
 }
 
 // PostDataJSON returns the request post data as a JS object.
-func (r *Request) PostDataJSON() string {
-	k6ext.Panic(r.ctx, "Request.postDataJSON() has not been implemented yet")
-	return ""
+// Form encoded post data is returned as an object of the form fields.
+// It returns null if the request has no post data.
+func (r *Request) PostDataJSON() goja.Value {
+	if r.postData == "" {
+		return goja.Null()
+	}
+
+	rt := r.vu.Runtime()
+	ct := r.AllHeaders()["content-type"]
+	if strings.HasPrefix(strings.ToLower(ct), "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(r.postData)
+		if err != nil {
+			k6ext.Panic(r.ctx, "parsing form post data: %w", err)
+		}
+		form := make(map[string]string, len(values))
+		for k := range values {
+			form[k] = values.Get(k)
+		}
+		return rt.ToValue(form)
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(r.postData), &v); err != nil {
+		k6ext.Panic(r.ctx, "parsing post data as JSON: %w", err)
+	}
+	return rt.ToValue(v)
 }
 
+// RedirectedFrom returns the request that was redirected to this request,
+// or nil if this request is not the result of a redirect.
 func (r *Request) RedirectedFrom() api.Request {
-	k6ext.Panic(r.ctx, "Request.redirectedFrom() has not been implemented yet")
-	return nil
+	if r.redirectedFrom == nil {
+		return nil
+	}
+	return r.redirectedFrom
 }
 
+// RedirectedTo returns the request that this request was redirected to,
+// or nil if this request was not redirected.
 func (r *Request) RedirectedTo() api.Request {
-	k6ext.Panic(r.ctx, "Request.redirectedTo() has not been implemented yet")
-	return nil
+	r.redirectMu.RLock()
+	defer r.redirectMu.RUnlock()
+
+	if r.redirectedTo == nil {
+		return nil
+	}
+	return r.redirectedTo
 }
 
 // ResourceType returns the request resource type.
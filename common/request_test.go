@@ -9,6 +9,7 @@ import (
 
 	"github.com/chromedp/cdproto/cdp"
 	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -75,6 +76,67 @@ func TestRequest(t *testing.T) {
 		assert.Equal(t, "value", req.HeaderValue("key").Export())
 	})
 
+	t.Run("Failure()", func(t *testing.T) {
+		t.Parallel()
+		vu := k6test.NewVU(t)
+		req, err := NewRequest(vu.Context(), NewRequestParams{event: evt})
+		require.NoError(t, err)
+		assert.True(t, goja.IsNull(req.Failure()))
+
+		req.setErrorText("net::ERR_FAILED")
+		assert.Equal(t, map[string]string{"errorText": "net::ERR_FAILED"}, req.Failure().Export())
+	})
+
+	t.Run("PostDataJSON()", func(t *testing.T) {
+		t.Parallel()
+
+		newReq := func(contentType, postData string) *Request {
+			vu := k6test.NewVU(t)
+			req, err := NewRequest(vu.Context(), NewRequestParams{
+				event: &network.EventRequestWillBeSent{
+					RequestID: network.RequestID("1234"),
+					Request: &network.Request{
+						URL:      "https://test/post",
+						Method:   "POST",
+						Headers:  network.Headers(map[string]any{"Content-Type": contentType}),
+						PostData: postData,
+					},
+					Timestamp: &ts,
+					WallTime:  &wt,
+				},
+			})
+			require.NoError(t, err)
+			return req
+		}
+
+		req := newReq("application/json", `{"a":1,"b":["c"]}`)
+		assert.Equal(t, map[string]any{"a": float64(1), "b": []any{"c"}}, req.PostDataJSON().Export())
+
+		req = newReq("application/x-www-form-urlencoded; charset=UTF-8", "a=1&b=two+words")
+		assert.Equal(t, map[string]string{"a": "1", "b": "two words"}, req.PostDataJSON().Export())
+
+		req = newReq("text/plain", "")
+		assert.True(t, goja.IsNull(req.PostDataJSON()))
+	})
+
+	t.Run("RedirectedFrom()", func(t *testing.T) {
+		t.Parallel()
+		vu := k6test.NewVU(t)
+		from, err := NewRequest(vu.Context(), NewRequestParams{event: evt})
+		require.NoError(t, err)
+		assert.Nil(t, from.RedirectedFrom())
+		assert.Nil(t, from.RedirectedTo())
+
+		to, err := NewRequest(vu.Context(), NewRequestParams{
+			event:         evt,
+			redirectChain: []*Request{from},
+		})
+		require.NoError(t, err)
+		from.setRedirectedTo(to)
+		assert.Equal(t, from, to.RedirectedFrom())
+		assert.Equal(t, to, from.RedirectedTo())
+	})
+
 	t.Run("Size()", func(t *testing.T) {
 		t.Parallel()
 		assert.Equal(t,
@@ -0,0 +1,467 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/dop251/goja"
+)
+
+// Ensure Route implements the api.Route interface.
+var _ api.Route = &Route{}
+
+// errRouteHandled is returned when a route is handled more than once.
+var errRouteHandled = errors.New("route is already handled")
+
+// routeErrorReasons maps the error codes accepted by Route.Abort
+// to the CDP network error reasons.
+var routeErrorReasons = map[string]network.ErrorReason{ //nolint:gochecknoglobals
+	"aborted":              network.ErrorReasonAborted,
+	"accessdenied":         network.ErrorReasonAccessDenied,
+	"addressunreachable":   network.ErrorReasonAddressUnreachable,
+	"blockedbyclient":      network.ErrorReasonBlockedByClient,
+	"blockedbyresponse":    network.ErrorReasonBlockedByResponse,
+	"connectionaborted":    network.ErrorReasonConnectionAborted,
+	"connectionclosed":     network.ErrorReasonConnectionClosed,
+	"connectionfailed":     network.ErrorReasonConnectionFailed,
+	"connectionrefused":    network.ErrorReasonConnectionRefused,
+	"connectionreset":      network.ErrorReasonConnectionReset,
+	"internetdisconnected": network.ErrorReasonInternetDisconnected,
+	"namenotresolved":      network.ErrorReasonNameNotResolved,
+	"timedout":             network.ErrorReasonTimedOut,
+	"failed":               network.ErrorReasonFailed,
+}
+
+// Route represents a request paused by the Fetch domain that is handed
+// over to a user route handler. The handler decides whether the request
+// is aborted, continued or fulfilled with a custom response.
+type Route struct {
+	ctx       context.Context
+	logger    *log.Logger
+	session   session
+	request   *Request
+	requestID fetch.RequestID
+
+	handledMu sync.Mutex
+	handled   bool
+}
+
+// NewRoute creates a new route for the paused request.
+func NewRoute(
+	ctx context.Context, s session, req *Request, rid fetch.RequestID, logger *log.Logger,
+) *Route {
+	return &Route{
+		ctx:       ctx,
+		logger:    logger,
+		session:   s,
+		request:   req,
+		requestID: rid,
+	}
+}
+
+// Abort aborts the request with the given error code.
+// The error code defaults to "failed".
+func (r *Route) Abort(errorCode string) {
+	r.logger.Debugf("Route:Abort", "rid:%s url:%s code:%q", r.requestID, r.request.URL(), errorCode)
+
+	if err := r.abort(errorCode); err != nil {
+		k6ext.Panic(r.ctx, "aborting request: %w", err)
+	}
+}
+
+func (r *Route) abort(errorCode string) error {
+	if errorCode == "" {
+		errorCode = "failed"
+	}
+	reason, ok := routeErrorReasons[strings.ToLower(errorCode)]
+	if !ok {
+		return fmt.Errorf("unknown error code %q", errorCode)
+	}
+	if err := r.startHandling(); err != nil {
+		return err
+	}
+
+	action := fetch.FailRequest(r.requestID, reason)
+	if err := action.Do(cdp.WithExecutor(r.ctx, r.session)); err != nil {
+		return fmt.Errorf("failing request: %w", err)
+	}
+
+	return nil
+}
+
+// Continue sends the request to the network with optional overrides.
+func (r *Route) Continue(opts goja.Value) {
+	r.logger.Debugf("Route:Continue", "rid:%s url:%s", r.requestID, r.request.URL())
+
+	copts := NewRouteContinueOptions()
+	if err := copts.Parse(r.ctx, opts); err != nil {
+		k6ext.Panic(r.ctx, "parsing continue options: %w", err)
+	}
+	if err := r.startHandling(); err != nil {
+		k6ext.Panic(r.ctx, "continuing request: %w", err)
+	}
+
+	action := fetch.ContinueRequest(r.requestID)
+	if copts.URL != "" {
+		action = action.WithURL(copts.URL)
+	}
+	if copts.Method != "" {
+		action = action.WithMethod(copts.Method)
+	}
+	if copts.Headers != nil {
+		action = action.WithHeaders(toFetchHeaders(copts.Headers))
+	}
+	if copts.PostData != nil {
+		action = action.WithPostData(base64.StdEncoding.EncodeToString(copts.PostData))
+	}
+	if err := action.Do(cdp.WithExecutor(r.ctx, r.session)); err != nil {
+		k6ext.Panic(r.ctx, "continuing request: %w", err)
+	}
+}
+
+// Fulfill responds to the request with the given response
+// without sending the request to the network.
+func (r *Route) Fulfill(opts goja.Value) {
+	r.logger.Debugf("Route:Fulfill", "rid:%s url:%s", r.requestID, r.request.URL())
+
+	fopts := NewRouteFulfillOptions()
+	if err := fopts.Parse(r.ctx, opts); err != nil {
+		k6ext.Panic(r.ctx, "parsing fulfill options: %w", err)
+	}
+	if err := r.fulfill(fopts); err != nil {
+		k6ext.Panic(r.ctx, "fulfilling request: %w", err)
+	}
+}
+
+func (r *Route) fulfill(opts *RouteFulfillOptions) error {
+	if err := r.startHandling(); err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(opts.Headers)+2)
+	for k, v := range opts.Headers {
+		headers[strings.ToLower(k)] = v
+	}
+	if opts.ContentType != "" {
+		headers["content-type"] = opts.ContentType
+	}
+	if _, ok := headers["content-length"]; !ok {
+		headers["content-length"] = strconv.Itoa(len(opts.Body))
+	}
+
+	action := fetch.FulfillRequest(r.requestID, opts.Status).
+		WithResponseHeaders(toFetchHeaders(headers)).
+		WithBody(base64.StdEncoding.EncodeToString(opts.Body))
+	if err := action.Do(cdp.WithExecutor(r.ctx, r.session)); err != nil {
+		return fmt.Errorf("fulfilling request: %w", err)
+	}
+
+	return nil
+}
+
+// Request returns the request that is being routed.
+func (r *Route) Request() api.Request {
+	return r.request
+}
+
+// startHandling marks the route as handled.
+// It returns an error if the route was already handled.
+func (r *Route) startHandling() error {
+	r.handledMu.Lock()
+	defer r.handledMu.Unlock()
+
+	if r.handled {
+		return errRouteHandled
+	}
+	r.handled = true
+
+	return nil
+}
+
+func (r *Route) isHandled() bool {
+	r.handledMu.Lock()
+	defer r.handledMu.Unlock()
+
+	return r.handled
+}
+
+func toFetchHeaders(headers map[string]string) []*fetch.HeaderEntry {
+	names := make([]string, 0, len(headers))
+	for n := range headers {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	entries := make([]*fetch.HeaderEntry, 0, len(headers))
+	for _, n := range names {
+		entries = append(entries, &fetch.HeaderEntry{Name: n, Value: headers[n]})
+	}
+
+	return entries
+}
+
+// urlMatcher reports whether a URL matches a route.
+type urlMatcher func(url string) bool
+
+// routeHandler is a route handler registered for the URLs it matches.
+type routeHandler struct {
+	url     goja.Value
+	handler goja.Value
+	// matcher matches the URLs of a glob or a regular expression.
+	// It's nil if the URL is a predicate function.
+	matcher   urlMatcher
+	predicate goja.Callable
+	handle    func(*Route) error
+
+	// queue runs the predicate and the handler on the event loop.
+	// It's nil for the handlers that don't call into JS, such as
+	// the handlers of the HAR files, which are called directly.
+	rt        *goja.Runtime
+	queue     *k6ext.TaskQueue
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// newRouteHandler returns a route handler that calls the handler on the
+// event loop, so it must be called on the event loop. The handler is called
+// while the VU waits on a promise, and it doesn't keep the event loop alive.
+// It stops once ctx or done is done, the iteration ends, or it's closed.
+func newRouteHandler(
+	ctx context.Context, done <-chan struct{}, url goja.Value, handler api.RouteHandler,
+) (*routeHandler, error) {
+	if handler.Call == nil {
+		return nil, errors.New("route handler must be a function")
+	}
+	if !gojaValueExists(url) {
+		return nil, errors.New("missing URL to match")
+	}
+
+	var (
+		vu = k6ext.GetVU(ctx)
+		rh = &routeHandler{
+			url:     url,
+			handler: handler.Value,
+			handle: func(r *Route) error {
+				if err := handler.Call(r); err != nil {
+					return fmt.Errorf("calling route handler: %w", err)
+				}
+				return nil
+			},
+			rt:     vu.Runtime(),
+			closed: make(chan struct{}),
+		}
+	)
+	if fn, ok := goja.AssertFunction(url); ok {
+		rh.predicate = fn
+	} else {
+		matcher, err := newURLMatcher(rh.rt, url)
+		if err != nil {
+			return nil, err
+		}
+		rh.matcher = matcher
+	}
+
+	rh.queue = k6ext.NewPassiveTaskQueue(vu)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		case <-rh.closed:
+		case <-rh.queue.Done():
+		}
+		rh.queue.Close()
+	}()
+
+	return rh, nil
+}
+
+// mightMatch reports whether the URL matches the glob or the regular
+// expression of the route handler. The URLs always might match a
+// predicate function, which is only called on the event loop.
+func (h *routeHandler) mightMatch(url string) bool {
+	return h.matcher == nil || h.matcher(url)
+}
+
+// route hands the route over to the handler if the handler matches the
+// URL of the request. The handlers that call into JS are called on the
+// event loop, and route waits for them to return.
+func (h *routeHandler) route(ctx context.Context, r *Route) error {
+	if h.queue == nil {
+		return h.handle(r)
+	}
+
+	var (
+		// claimed makes sure that the route is not handled if
+		// route has already given up waiting for the handler.
+		claimed atomic.Bool
+		done    = make(chan error, 1)
+	)
+	queued := h.queue.Queue(func() error {
+		if claimed.CompareAndSwap(false, true) {
+			done <- h.matchAndHandle(r)
+		}
+		return nil
+	})
+	if !queued {
+		// The route handler was removed, or its page
+		// or browser context was closed in the meantime.
+		return nil
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-h.queue.Done():
+		// The queue was closed before it ran the handler, such as
+		// when the iteration ended, so the route is left unhandled.
+		if claimed.CompareAndSwap(false, true) {
+			return nil
+		}
+		return <-done
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for route handler: %w", ctx.Err())
+	}
+}
+
+// matchAndHandle calls the predicate and the handler of the route.
+// It must be called on the event loop.
+func (h *routeHandler) matchAndHandle(r *Route) error {
+	if h.predicate != nil {
+		v, err := h.predicate(goja.Undefined(), h.rt.ToValue(r.request.URL()))
+		if err != nil {
+			return fmt.Errorf("calling route URL predicate: %w", err)
+		}
+		if !v.ToBoolean() {
+			return nil
+		}
+	}
+
+	return h.handle(r)
+}
+
+// close stops the route handler from handling the requests.
+func (h *routeHandler) close() {
+	if h.closed == nil {
+		return
+	}
+	h.closeOnce.Do(func() { close(h.closed) })
+}
+
+// equals reports whether the route handler was registered with the
+// given url and, if it's given, the given handler.
+func (h *routeHandler) equals(url, handler goja.Value) bool {
+	if !h.url.StrictEquals(url) && h.url.String() != url.String() {
+		return false
+	}
+	if !gojaValueExists(handler) {
+		return true
+	}
+	return h.handler != nil && h.handler.StrictEquals(handler)
+}
+
+// newURLMatcher returns a URL matcher for a glob pattern
+// string or a regular expression. Since the matcher is called
+// off the event loop, predicate functions are not supported.
+func newURLMatcher(rt *goja.Runtime, url goja.Value) (urlMatcher, error) {
+	if !gojaValueExists(url) {
+		return nil, errors.New("missing URL to match")
+	}
+	if _, ok := goja.AssertFunction(url); ok {
+		return nil, errors.New("URL must be a string or a regular expression, got a function")
+	}
+	if obj, ok := url.(*goja.Object); ok && obj.ClassName() == "RegExp" {
+		re, err := jsRegExpToRegexp(obj)
+		if err != nil {
+			return nil, err
+		}
+		return re.MatchString, nil
+	}
+
+	glob := url.String()
+	re, err := regexp.Compile(globToRegexp(glob))
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL glob %q: %w", glob, err)
+	}
+
+	return re.MatchString, nil
+}
+
+// jsRegExpToRegexp converts a JS RegExp object to a Go regular expression.
+func jsRegExpToRegexp(obj *goja.Object) (*regexp.Regexp, error) {
+	var (
+		source = obj.Get("source").String()
+		flags  = obj.Get("flags").String()
+		prefix string
+	)
+	for _, f := range flags {
+		switch f {
+		case 'i', 'm', 's':
+			prefix += string(f)
+		}
+	}
+	if prefix != "" {
+		source = "(?" + prefix + ")" + source
+	}
+	re, err := regexp.Compile(source)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL regular expression %q: %w", source, err)
+	}
+
+	return re, nil
+}
+
+// globToRegexp converts a URL glob pattern to a regular expression.
+// A single asterisk matches any characters except "/", a double
+// asterisk matches any characters, "?" matches a single character
+// and "{a,b}" matches any of the comma separated alternatives.
+func globToRegexp(glob string) string {
+	var (
+		sb      strings.Builder
+		inGroup bool
+	)
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		case c == '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case c == '?':
+			sb.WriteString(".")
+		case c == '{':
+			inGroup = true
+			sb.WriteString("(")
+		case c == '}' && inGroup:
+			inGroup = false
+			sb.WriteString(")")
+		case c == ',' && inGroup:
+			sb.WriteString("|")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	return sb.String()
+}
@@ -0,0 +1,166 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dop251/goja"
+
+	"github.com/grafana/xk6-browser/k6ext"
+)
+
+// RouteContinueOptions are the options for overriding a request
+// that is continued by a route handler.
+type RouteContinueOptions struct {
+	URL      string            `json:"url"`
+	Method   string            `json:"method"`
+	Headers  map[string]string `json:"headers"`
+	PostData []byte            `json:"postData"`
+}
+
+// RouteFulfillOptions are the options for the response that
+// fulfills a request in a route handler.
+type RouteFulfillOptions struct {
+	Status      int64             `json:"status"`
+	Headers     map[string]string `json:"headers"`
+	ContentType string            `json:"contentType"`
+	Body        []byte            `json:"body"`
+	Path        string            `json:"path"`
+}
+
+// NewRouteContinueOptions returns a new RouteContinueOptions.
+func NewRouteContinueOptions() *RouteContinueOptions {
+	return &RouteContinueOptions{}
+}
+
+// Parse parses the route continue options.
+func (o *RouteContinueOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "url":
+			o.URL = obj.Get(k).String()
+		case "method":
+			o.Method = strings.ToUpper(obj.Get(k).String())
+		case "headers":
+			o.Headers = make(map[string]string)
+			if err := rt.ExportTo(obj.Get(k), &o.Headers); err != nil {
+				return fmt.Errorf("parsing route headers: %w", err)
+			}
+		case "postData":
+			o.PostData = gojaValueToBytes(obj.Get(k))
+		}
+	}
+
+	return nil
+}
+
+// NewRouteFulfillOptions returns a new RouteFulfillOptions.
+func NewRouteFulfillOptions() *RouteFulfillOptions {
+	return &RouteFulfillOptions{
+		Status: 200,
+	}
+}
+
+// Parse parses the route fulfill options.
+// If a path is given, the body is read from the file.
+func (o *RouteFulfillOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "status":
+			o.Status = obj.Get(k).ToInteger()
+		case "headers":
+			o.Headers = make(map[string]string)
+			if err := rt.ExportTo(obj.Get(k), &o.Headers); err != nil {
+				return fmt.Errorf("parsing route headers: %w", err)
+			}
+		case "contentType":
+			o.ContentType = obj.Get(k).String()
+		case "body":
+			o.Body = gojaValueToBytes(obj.Get(k))
+		case "path":
+			o.Path = obj.Get(k).String()
+		}
+	}
+	if o.Path != "" {
+		body, err := os.ReadFile(o.Path)
+		if err != nil {
+			return fmt.Errorf("reading route body from %q: %w", o.Path, err)
+		}
+		o.Body = body
+	}
+
+	return nil
+}
+
+// gojaValueToBytes returns the bytes of a string or an ArrayBuffer value.
+func gojaValueToBytes(v goja.Value) []byte {
+	if !gojaValueExists(v) {
+		return nil
+	}
+	if ab, ok := v.Export().(goja.ArrayBuffer); ok {
+		return ab.Bytes()
+	}
+	return []byte(v.String())
+}
+
+// Policies for requests that are not found in the HAR file of routeFromHAR.
+const (
+	// HARNotFoundAbort aborts the requests that are not found in the HAR file.
+	HARNotFoundAbort = "abort"
+	// HARNotFoundFallback falls back to the next route handler
+	// or the network for requests that are not found in the HAR file.
+	HARNotFoundFallback = "fallback"
+)
+
+// RouteFromHAROptions are the options for answering requests from a HAR file.
+type RouteFromHAROptions struct {
+	URL      goja.Value `js:"url"`
+	NotFound string     `js:"notFound"`
+	Update   bool       `js:"update"`
+}
+
+// NewRouteFromHAROptions returns a new RouteFromHAROptions.
+func NewRouteFromHAROptions() *RouteFromHAROptions {
+	return &RouteFromHAROptions{
+		URL:      goja.Undefined(),
+		NotFound: HARNotFoundAbort,
+	}
+}
+
+// Parse parses the routeFromHAR options.
+func (o *RouteFromHAROptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "url":
+			o.URL = obj.Get(k)
+		case "notFound":
+			switch nf := obj.Get(k).String(); nf {
+			case HARNotFoundAbort, HARNotFoundFallback:
+				o.NotFound = nf
+			default:
+				return fmt.Errorf("notFound must be %q or %q, got %q", HARNotFoundAbort, HARNotFoundFallback, nf)
+			}
+		case "update":
+			o.Update = obj.Get(k).ToBoolean()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/dop251/goja"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLMatcher(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name, url string
+		matches   map[string]bool
+	}{
+		{
+			name: "glob_exact",
+			url:  `'https://example.com/'`,
+			matches: map[string]bool{
+				"https://example.com/":      true,
+				"https://example.com/index": false,
+			},
+		},
+		{
+			name: "glob_single_star",
+			url:  `'https://example.com/*.png'`,
+			matches: map[string]bool{
+				"https://example.com/logo.png":     true,
+				"https://example.com/img/logo.png": false,
+			},
+		},
+		{
+			name: "glob_double_star",
+			url:  `'**/*.{png,jpg}'`,
+			matches: map[string]bool{
+				"https://example.com/img/logo.png": true,
+				"https://example.com/img/logo.jpg": true,
+				"https://example.com/img/logo.gif": false,
+			},
+		},
+		{
+			name: "glob_question_mark",
+			url:  `'**/v?/users'`,
+			matches: map[string]bool{
+				"https://example.com/v1/users":  true,
+				"https://example.com/v10/users": false,
+			},
+		},
+		{
+			name: "regexp",
+			url:  `/\/api\/v\d+\//i`,
+			matches: map[string]bool{
+				"https://example.com/API/v2/users": true,
+				"https://example.com/api/users":    false,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			rt := goja.New()
+			url, err := rt.RunString(tc.url)
+			require.NoError(t, err)
+			matcher, err := newURLMatcher(rt, url)
+			require.NoError(t, err)
+			for u, want := range tc.matches {
+				assert.Equal(t, want, matcher(u), u)
+			}
+		})
+	}
+}
+
+func TestURLMatcherPredicate(t *testing.T) {
+	t.Parallel()
+
+	rt := goja.New()
+	url, err := rt.RunString(`(url) => url.startsWith('https://example.com/api')`)
+	require.NoError(t, err)
+	_, err = newURLMatcher(rt, url)
+	assert.ErrorContains(t, err, "got a function")
+}
+
+func TestRouteHandlerEquals(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+	rt := vu.Runtime()
+	handler, err := rt.RunString(`() => {}`)
+	require.NoError(t, err)
+	other, err := rt.RunString(`() => {}`)
+	require.NoError(t, err)
+
+	rh, err := newRouteHandler(vu.Context(), nil, rt.ToValue("**/api/*"), api.RouteHandler{
+		Value: handler,
+		Call:  func(api.Route) error { return nil },
+	})
+	require.NoError(t, err)
+	defer rh.close()
+
+	assert.True(t, rh.equals(rt.ToValue("**/api/*"), nil))
+	assert.True(t, rh.equals(rt.ToValue("**/api/*"), goja.Undefined()))
+	assert.True(t, rh.equals(rt.ToValue("**/api/*"), handler))
+	assert.False(t, rh.equals(rt.ToValue("**/api/*"), other))
+	assert.False(t, rh.equals(rt.ToValue("**/*"), handler))
+}
@@ -0,0 +1,228 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common/js"
+	"github.com/grafana/xk6-browser/k6ext"
+
+	"github.com/chromedp/cdproto/cdp"
+	cdppage "github.com/chromedp/cdproto/page"
+	"github.com/dop251/goja"
+)
+
+// StorageStateOptions are the options of BrowserContext.StorageState.
+type StorageStateOptions struct {
+	Path string `js:"path"`
+}
+
+// NewStorageStateOptions returns a new StorageStateOptions.
+func NewStorageStateOptions() *StorageStateOptions {
+	return &StorageStateOptions{}
+}
+
+// Parse parses the storage state options.
+func (o *StorageStateOptions) Parse(ctx context.Context, opts goja.Value) error {
+	if !gojaValueExists(opts) {
+		return nil
+	}
+	rt := k6ext.Runtime(ctx)
+	obj := opts.ToObject(rt)
+	for _, k := range obj.Keys() {
+		switch k {
+		case "path":
+			o.Path = obj.Get(k).String()
+		}
+	}
+
+	return nil
+}
+
+// parseStorageState parses the storageState option of a browser context.
+// The storage state is either the path of a storage state file or an
+// object, such as the one returned by BrowserContext.storageState().
+func parseStorageState(ctx context.Context, v goja.Value) (*api.StorageState, error) {
+	if !gojaValueExists(v) {
+		return nil, nil //nolint:nilnil
+	}
+
+	var (
+		buf []byte
+		err error
+	)
+	if path, ok := v.Export().(string); ok {
+		if buf, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("reading storage state: %w", err)
+		}
+	} else if buf, err = json.Marshal(v.Export()); err != nil {
+		return nil, fmt.Errorf("encoding storage state: %w", err)
+	}
+
+	var state api.StorageState
+	if err := json.Unmarshal(buf, &state); err != nil {
+		return nil, fmt.Errorf("parsing storage state: %w", err)
+	}
+	for _, c := range state.Cookies {
+		if c.Name == "" || c.Domain == "" {
+			return nil, errors.New("parsing storage state: cookie name and domain must be set")
+		}
+	}
+
+	return &state, nil
+}
+
+// framesByOrigin returns a frame for each HTTP(S) origin
+// of the frames of the pages of this browser context.
+func (b *BrowserContext) framesByOrigin() map[string]*Frame {
+	frames := make(map[string]*Frame)
+	for _, p := range b.getPages() {
+		for _, f := range p.Frames() {
+			frame, ok := f.(*Frame)
+			if !ok {
+				continue
+			}
+			origin, err := originOf(frame.URL())
+			if err != nil {
+				continue
+			}
+			if _, ok := frames[origin]; !ok {
+				frames[origin] = frame
+			}
+		}
+	}
+
+	return frames
+}
+
+// localStorageOf returns the local storage items of the origin of the frame.
+func localStorageOf(f *Frame) ([]*api.LocalStorageItem, error) {
+	f.waitForExecutionContext(mainWorld)
+
+	var (
+		rt   = f.vu.Runtime()
+		opts = evalOptions{
+			forceCallable: true,
+			returnByValue: true,
+		}
+		fn = rt.ToValue(`() => JSON.stringify(Object.keys(localStorage).map(
+			name => ({ name, value: localStorage.getItem(name) })
+		))`)
+	)
+	res, err := f.evaluate(f.ctx, mainWorld, opts, fn)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := res.(goja.Value)
+	if !ok || !gojaValueExists(v) {
+		return nil, nil
+	}
+
+	var items []*api.LocalStorageItem
+	if err := json.Unmarshal([]byte(v.String()), &items); err != nil {
+		return nil, fmt.Errorf("parsing local storage: %w", err)
+	}
+
+	return items, nil
+}
+
+func writeStorageState(path string, state *api.StorageState) error {
+	buf, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding storage state: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("creating storage state directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, buf, 0o600); err != nil {
+		return fmt.Errorf("writing storage state: %w", err)
+	}
+
+	return nil
+}
+
+// restoreStorageState adds the cookies of the storage state to this browser
+// context, and restores the local storage of each of its origins once, on the
+// first document of the origin, before the scripts of the document run.
+func (b *BrowserContext) restoreStorageState(state *api.StorageState) error {
+	if err := b.setCookies(state.Cookies); err != nil {
+		return fmt.Errorf("restoring cookies: %w", err)
+	}
+
+	b.localStorageMu.Lock()
+	defer b.localStorageMu.Unlock()
+
+	b.localStorage = state.Origins
+
+	return nil
+}
+
+// addLocalStorageScript adds the script that restores the local storage of
+// the origins that are not restored yet to the new documents of the page.
+// It must be called with localStorageMu held.
+func (b *BrowserContext) addLocalStorageScript(p *Page) error {
+	if len(b.localStorage) == 0 {
+		return nil
+	}
+
+	origins, err := json.Marshal(b.localStorage)
+	if err != nil {
+		return fmt.Errorf("encoding local storage: %w", err)
+	}
+	source := fmt.Sprintf("(%s)(%s, %q);", js.RestoreStorageState, origins, webVitalBinding)
+	id, err := cdppage.AddScriptToEvaluateOnNewDocument(source).Do(cdp.WithExecutor(p.ctx, p.session))
+	if err != nil {
+		return fmt.Errorf("adding local storage script: %w", err)
+	}
+	b.localStorageScripts[p] = id
+
+	return nil
+}
+
+// localStorageRestored stops restoring the local storage of the origin once
+// it's restored, by replacing the local storage scripts of the pages with
+// ones for the remaining origins.
+func (b *BrowserContext) localStorageRestored(origin string) {
+	b.logger.Debugf("BrowserContext:localStorageRestored", "bctxid:%v origin:%q", b.id, origin)
+
+	b.localStorageMu.Lock()
+	defer b.localStorageMu.Unlock()
+
+	remaining := make([]*api.StorageStateOrigin, 0, len(b.localStorage))
+	for _, o := range b.localStorage {
+		if o.Origin != origin {
+			remaining = append(remaining, o)
+		}
+	}
+	if len(remaining) == len(b.localStorage) {
+		return // already restored
+	}
+	b.localStorage = remaining
+
+	pages := make([]*Page, 0, len(b.localStorageScripts))
+	for p := range b.localStorageScripts {
+		pages = append(pages, p)
+	}
+	for _, p := range pages {
+		id := b.localStorageScripts[p]
+		delete(b.localStorageScripts, p)
+		if p.IsClosed() {
+			continue
+		}
+		action := cdppage.RemoveScriptToEvaluateOnNewDocument(id)
+		if err := action.Do(cdp.WithExecutor(p.ctx, p.session)); err != nil {
+			b.logger.Debugf("BrowserContext:localStorageRestored", "removing local storage script: %v", err)
+			continue
+		}
+		if err := b.addLocalStorageScript(p); err != nil {
+			b.logger.Errorf("BrowserContext:localStorageRestored", "%v", err)
+		}
+	}
+}
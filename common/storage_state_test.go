@@ -0,0 +1,74 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStorageState(t *testing.T) {
+	t.Parallel()
+
+	want := &api.StorageState{
+		Cookies: []*api.Cookie{
+			{Name: "session", Value: "abc", Domain: "example.com", Path: "/", Expires: -1, HTTPOnly: true},
+		},
+		Origins: []*api.StorageStateOrigin{
+			{
+				Origin:       "https://example.com",
+				LocalStorage: []*api.LocalStorageItem{{Name: "token", Value: "xyz"}},
+			},
+		},
+	}
+
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		v, err := vu.Runtime().RunString(`({
+			cookies: [{ name: 'session', value: 'abc', domain: 'example.com', path: '/', expires: -1, httpOnly: true }],
+			origins: [{ origin: 'https://example.com', localStorage: [{ name: 'token', value: 'xyz' }] }],
+		})`)
+		require.NoError(t, err)
+
+		state, err := parseStorageState(vu.Context(), v)
+		require.NoError(t, err)
+		assert.Equal(t, want, state)
+	})
+
+	t.Run("path", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		path := filepath.Join(t.TempDir(), "state", "auth.json")
+		require.NoError(t, writeStorageState(path, want))
+
+		state, err := parseStorageState(vu.Context(), vu.ToGojaValue(path))
+		require.NoError(t, err)
+		assert.Equal(t, want, state)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		_, err := parseStorageState(vu.Context(), vu.ToGojaValue(filepath.Join(t.TempDir(), "missing.json")))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("invalid_cookie", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		_, err := parseStorageState(vu.Context(), vu.ToGojaValue(map[string]any{
+			"cookies": []map[string]any{{"name": "session", "value": "abc"}},
+		}))
+		assert.ErrorContains(t, err, "cookie name and domain must be set")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -67,6 +68,14 @@ type Credentials struct {
 	Password string `js:"password"`
 }
 
+// Proxy holds the proxy settings of a browser context.
+type Proxy struct {
+	Server   string `js:"server"`
+	Bypass   string `js:"bypass"`
+	Username string `js:"username"`
+	Password string `js:"password"`
+}
+
 // DOMElementState represents a DOM element state.
 type DOMElementState int
 
@@ -128,6 +137,14 @@ func NewEmulatedSize(viewport *Viewport, screen *Screen) *EmulatedSize {
 	}
 }
 
+// EventSourceMessage is a message received by an EventSource (server-sent events).
+type EventSourceMessage struct {
+	URL       string `js:"url"`
+	EventName string `js:"eventName"`
+	EventID   string `js:"eventId"`
+	Data      string `js:"data"`
+}
+
 type Geolocation struct {
 	Latitude  float64 `js:"latitude"`
 	Longitude float64 `js:"longitude"`
@@ -174,6 +191,83 @@ func (g *Geolocation) Parse(ctx context.Context, opts goja.Value) error {
 	return nil
 }
 
+// NetworkConditions holds the emulated network conditions.
+// Latency is in milliseconds and throughputs are in bytes per second.
+// A negative throughput disables throttling.
+type NetworkConditions struct {
+	Latency            float64 `js:"latency"`
+	DownloadThroughput float64 `js:"downloadThroughput"`
+	UploadThroughput   float64 `js:"uploadThroughput"`
+}
+
+// networkConditionsPresets are the built-in network conditions
+// that can be referred to by name. They match the Chrome DevTools presets.
+var networkConditionsPresets = map[string]NetworkConditions{ //nolint:gochecknoglobals
+	"Slow 3G": {
+		Latency:            400 * 5,
+		DownloadThroughput: 500 * 1000 / 8 * 0.8,
+		UploadThroughput:   500 * 1000 / 8 * 0.8,
+	},
+	"Fast 3G": {
+		Latency:            150 * 3.75,
+		DownloadThroughput: 1.6 * 1000 * 1000 / 8 * 0.9,
+		UploadThroughput:   750 * 1000 / 8 * 0.9,
+	},
+	"4G": {
+		Latency:            60 * 2.75,
+		DownloadThroughput: 9 * 1000 * 1000 / 8 * 0.9,
+		UploadThroughput:   1.5 * 1000 * 1000 / 8 * 0.9,
+	},
+}
+
+// NewNetworkConditions returns network conditions without throughput limits.
+func NewNetworkConditions() *NetworkConditions {
+	return &NetworkConditions{
+		DownloadThroughput: -1,
+		UploadThroughput:   -1,
+	}
+}
+
+// Parse parses the network conditions from a preset name
+// such as "Slow 3G", or from an object.
+func (n *NetworkConditions) Parse(ctx context.Context, conditions goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if conditions == nil || goja.IsUndefined(conditions) || goja.IsNull(conditions) {
+		return nil
+	}
+	if name, ok := conditions.Export().(string); ok {
+		preset, ok := networkConditionsPresets[name]
+		if !ok {
+			return fmt.Errorf(`unknown network conditions preset %q: must be one of "Slow 3G", "Fast 3G" or "4G"`, name)
+		}
+		*n = preset
+		return nil
+	}
+
+	opts := conditions.ToObject(rt)
+	for _, k := range opts.Keys() {
+		switch k {
+		case "latency":
+			n.Latency = opts.Get(k).ToFloat()
+		case "downloadThroughput":
+			n.DownloadThroughput = opts.Get(k).ToFloat()
+		case "uploadThroughput":
+			n.UploadThroughput = opts.Get(k).ToFloat()
+		}
+	}
+	if n.Latency < 0 {
+		return fmt.Errorf(`invalid latency "%.2f": precondition 0 <= LATENCY failed`, n.Latency)
+	}
+	if n.DownloadThroughput < 0 {
+		n.DownloadThroughput = -1
+	}
+	if n.UploadThroughput < 0 {
+		n.UploadThroughput = -1
+	}
+
+	return nil
+}
+
 // ImageFormat represents an image file format.
 type ImageFormat string
 
@@ -420,6 +514,16 @@ func (r *ReducedMotion) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ServiceWorkers represents whether a browser context allows
+// sites to register service workers.
+type ServiceWorkers string
+
+// Valid service workers options.
+const (
+	ServiceWorkersAllow ServiceWorkers = "allow"
+	ServiceWorkersBlock ServiceWorkers = "block"
+)
+
 type ResourceTiming struct {
 	StartTime             float64 `js:"startTime"`
 	DomainLookupStart     float64 `js:"domainLookupStart"`
@@ -544,6 +648,45 @@ func (v *Viewport) calculateInset(headless bool, os string) {
 	v.Height += inset.Height
 }
 
+// NewProxy returns empty proxy settings.
+func NewProxy() *Proxy {
+	return &Proxy{}
+}
+
+// Parse parses the proxy settings. The server is required and
+// the bypass list is a comma separated list of hosts.
+func (p *Proxy) Parse(ctx context.Context, proxy goja.Value) error {
+	rt := k6ext.Runtime(ctx)
+	if proxy != nil && !goja.IsUndefined(proxy) && !goja.IsNull(proxy) {
+		proxy := proxy.ToObject(rt)
+		for _, k := range proxy.Keys() {
+			switch k {
+			case "server":
+				p.Server = proxy.Get(k).String()
+			case "bypass":
+				p.Bypass = proxy.Get(k).String()
+			case "username":
+				p.Username = proxy.Get(k).String()
+			case "password":
+				p.Password = proxy.Get(k).String()
+			}
+		}
+	}
+	if p.Server == "" {
+		return errors.New("proxy server must be set")
+	}
+	return nil
+}
+
+// credentials returns the credentials for the proxy
+// authentication, or nil if there is no username.
+func (p *Proxy) credentials() *Credentials {
+	if p == nil || p.Username == "" {
+		return nil
+	}
+	return &Credentials{Username: p.Username, Password: p.Password}
+}
+
 func NewCredentials() *Credentials {
 	return &Credentials{}
 }
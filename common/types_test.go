@@ -3,6 +3,8 @@ package common
 import (
 	"testing"
 
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -129,3 +131,100 @@ func TestLifecycleEventUnmarshalText(t *testing.T) {
 				`must be one of: load, domcontentloaded, networkidle`)
 	})
 }
+
+func TestNetworkConditionsParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		conditions any
+		want       NetworkConditions
+		wantErr    string
+	}{
+		{
+			name:       "preset",
+			conditions: "Slow 3G",
+			want:       networkConditionsPresets["Slow 3G"],
+		},
+		{
+			name:       "unknown_preset",
+			conditions: "5G",
+			wantErr:    `unknown network conditions preset "5G"`,
+		},
+		{
+			name: "custom",
+			conditions: map[string]any{
+				"latency":            100,
+				"downloadThroughput": 1000,
+			},
+			want: NetworkConditions{
+				Latency:            100,
+				DownloadThroughput: 1000,
+				UploadThroughput:   -1,
+			},
+		},
+		{
+			name: "negative_throughput",
+			conditions: map[string]any{
+				"uploadThroughput": -10,
+			},
+			want: NetworkConditions{
+				DownloadThroughput: -1,
+				UploadThroughput:   -1,
+			},
+		},
+		{
+			name: "negative_latency",
+			conditions: map[string]any{
+				"latency": -1,
+			},
+			wantErr: "invalid latency",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vu := k6test.NewVU(t)
+			nc := NewNetworkConditions()
+			err := nc.Parse(vu.Context(), vu.ToGojaValue(tt.conditions))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *nc)
+		})
+	}
+}
+
+func TestProxyParse(t *testing.T) {
+	t.Parallel()
+
+	vu := k6test.NewVU(t)
+
+	p := NewProxy()
+	err := p.Parse(vu.Context(), vu.ToGojaValue(map[string]any{
+		"server":   "http://proxy.test:3128",
+		"bypass":   "localhost,.internal.test",
+		"username": "user",
+		"password": "pass",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, &Proxy{
+		Server:   "http://proxy.test:3128",
+		Bypass:   "localhost,.internal.test",
+		Username: "user",
+		Password: "pass",
+	}, p)
+	assert.Equal(t, &Credentials{Username: "user", Password: "pass"}, p.credentials())
+
+	p = NewProxy()
+	err = p.Parse(vu.Context(), vu.ToGojaValue(map[string]any{"server": "socks5://proxy.test"}))
+	require.NoError(t, err)
+	assert.Nil(t, p.credentials(), "should not authenticate without a username")
+
+	err = NewProxy().Parse(vu.Context(), vu.ToGojaValue(map[string]any{"username": "user"}))
+	require.ErrorContains(t, err, "proxy server must be set")
+}
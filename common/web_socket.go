@@ -0,0 +1,128 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/log"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// Ensure WebSocket implements the api.WebSocket interface.
+var _ api.WebSocket = &WebSocket{}
+
+// WebSocketFrame is a frame sent or received by a WebSocket.
+type WebSocketFrame struct {
+	Opcode  float64 `js:"opcode"`
+	Payload string  `js:"payload"`
+}
+
+// WebSocket represents a WebSocket connection opened by a page.
+type WebSocket struct {
+	BaseEventEmitter
+
+	ctx       context.Context
+	done      <-chan struct{}
+	logger    *log.Logger
+	requestID network.RequestID
+	url       string
+	pageURL   string
+
+	mu          sync.RWMutex
+	handshakeAt *cdp.MonotonicTime
+	connected   bool
+	closed      bool
+}
+
+// NewWebSocket creates a new WebSocket opened by the page at pageURL.
+// The done channel is closed when the page is closed.
+func NewWebSocket(
+	ctx context.Context, done <-chan struct{}, rid network.RequestID, url, pageURL string, logger *log.Logger,
+) *WebSocket {
+	return &WebSocket{
+		BaseEventEmitter: NewBaseEventEmitter(ctx),
+		ctx:              ctx,
+		done:             done,
+		logger:           logger,
+		requestID:        rid,
+		url:              url,
+		pageURL:          pageURL,
+	}
+}
+
+// IsClosed returns true if the WebSocket is closed.
+func (w *WebSocket) IsClosed() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.closed
+}
+
+// On calls the handler for the given WebSocket event:
+// "framesent" and "framereceived" with a WebSocketFrame,
+// "socketerror" with the error message and "close" with the WebSocket.
+// The handler is called on the event loop until the page is closed
+// or the iteration ends.
+func (w *WebSocket) On(event string, handler func(any) error) error {
+	switch event {
+	case EventWebSocketClose, EventWebSocketFrameReceived, EventWebSocketFrameSent, EventWebSocketError:
+	default:
+		return fmt.Errorf("unknown websocket event: %q", event)
+	}
+	handleEvents(w.ctx, w.done, w, []string{event}, handler, w.logger)
+
+	return nil
+}
+
+// URL returns the URL of the WebSocket.
+func (w *WebSocket) URL() string {
+	return w.url
+}
+
+// handshake records the time the opening handshake request was sent.
+func (w *WebSocket) handshake(ts *cdp.MonotonicTime) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handshakeAt = ts
+}
+
+// connect marks the WebSocket as connected when the opening handshake
+// response is received, and returns how long the handshake took.
+func (w *WebSocket) connect(ts *cdp.MonotonicTime) time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.connected = true
+
+	return monotonicSince(w.handshakeAt, ts)
+}
+
+// close marks the WebSocket as closed. It returns how long the session
+// lasted and whether the WebSocket was connected, and ok is false if
+// the WebSocket was already closed.
+func (w *WebSocket) close(ts *cdp.MonotonicTime) (session time.Duration, connected, ok bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, false, false
+	}
+	w.closed = true
+
+	return monotonicSince(w.handshakeAt, ts), w.connected, true
+}
+
+// monotonicSince returns the duration between the start and end
+// CDP timestamps, or zero if any of them is missing.
+func monotonicSince(start, end *cdp.MonotonicTime) time.Duration {
+	if start == nil || end == nil {
+		return 0
+	}
+	return end.Time().Sub(start.Time())
+}
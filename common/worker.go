@@ -2,13 +2,17 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/grafana/xk6-browser/api"
-	"github.com/grafana/xk6-browser/k6error"
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/log"
 
+	"github.com/chromedp/cdproto"
 	"github.com/chromedp/cdproto/cdp"
-	"github.com/chromedp/cdproto/log"
+	cdplog "github.com/chromedp/cdproto/log"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/cdproto/target"
@@ -24,19 +28,26 @@ type Worker struct {
 
 	ctx     context.Context
 	session session
+	logger  *log.Logger
 
 	targetID target.ID
 	url      string
+
+	execCtxMu    sync.RWMutex
+	execCtx      *ExecutionContext
+	execCtxReady chan struct{}
 }
 
 // NewWorker creates a new page viewport.
-func NewWorker(ctx context.Context, s session, id target.ID, url string) (*Worker, error) {
+func NewWorker(ctx context.Context, s session, id target.ID, url string, logger *log.Logger) (*Worker, error) {
 	w := Worker{
 		BaseEventEmitter: NewBaseEventEmitter(ctx),
 		ctx:              ctx,
 		session:          s,
+		logger:           logger,
 		targetID:         id,
 		url:              url,
+		execCtxReady:     make(chan struct{}),
 	}
 	if err := w.initEvents(); err != nil {
 		return nil, err
@@ -50,29 +61,93 @@ func (w *Worker) didClose() {
 }
 
 func (w *Worker) initEvents() error {
+	// A worker has a single execution context that is reported
+	// after enabling the runtime domain.
+	evCtx, evCancel := context.WithCancel(w.ctx)
+	chEvHandler := make(chan Event)
+	w.session.on(evCtx, []string{cdproto.EventRuntimeExecutionContextCreated}, chEvHandler)
+	go func() {
+		defer evCancel()
+		select {
+		case <-evCtx.Done():
+		case ev := <-chEvHandler:
+			if ev, ok := ev.data.(*runtime.EventExecutionContextCreated); ok {
+				w.onExecutionContextCreated(ev)
+			}
+		}
+	}()
+
 	actions := []Action{
-		log.Enable(),
+		cdplog.Enable(),
 		network.Enable(),
+		runtime.Enable(),
 		runtime.RunIfWaitingForDebugger(),
 	}
 	for _, action := range actions {
 		if err := action.Do(cdp.WithExecutor(w.ctx, w.session)); err != nil {
+			evCancel()
 			return fmt.Errorf("protocol error while initializing worker %T: %w", action, err)
 		}
 	}
 	return nil
 }
 
+func (w *Worker) onExecutionContextCreated(event *runtime.EventExecutionContextCreated) {
+	w.logger.Debugf("Worker:onExecutionContextCreated",
+		"sid:%v tid:%v ectxid:%d", w.session.ID(), w.targetID, event.Context.ID)
+
+	w.execCtxMu.Lock()
+	w.execCtx = NewExecutionContext(w.ctx, w.session, nil, event.Context.ID, w.logger)
+	w.execCtxMu.Unlock()
+	close(w.execCtxReady)
+}
+
+// executionContext waits for the execution context of the worker and returns it.
+func (w *Worker) executionContext() (*ExecutionContext, error) {
+	select {
+	case <-w.execCtxReady:
+	case <-w.session.Done():
+		return nil, errors.New("worker is closed")
+	case <-w.ctx.Done():
+		return nil, fmt.Errorf("waiting for worker execution context: %w", w.ctx.Err())
+	}
+
+	w.execCtxMu.RLock()
+	defer w.execCtxMu.RUnlock()
+
+	return w.execCtx, nil
+}
+
 // Evaluate evaluates a page function in the context of the web worker.
 func (w *Worker) Evaluate(pageFunc goja.Value, args ...goja.Value) any {
-	// TODO: implement
-	return nil
+	w.logger.Debugf("Worker:Evaluate", "sid:%v tid:%v url:%q", w.session.ID(), w.targetID, w.url)
+
+	ec, err := w.executionContext()
+	if err != nil {
+		k6ext.Panic(w.ctx, "evaluating JS in worker: %w", err)
+	}
+	res, err := ec.Eval(w.ctx, pageFunc, args...)
+	if err != nil {
+		k6ext.Panic(w.ctx, "evaluating JS in worker: %w", err)
+	}
+
+	return res
 }
 
 // EvaluateHandle evaluates a page function in the context of the web worker and returns a JS handle.
 func (w *Worker) EvaluateHandle(pageFunc goja.Value, args ...goja.Value) (api.JSHandle, error) {
-	// TODO: implement
-	return nil, fmt.Errorf("Worker.EvaluateHandle has not been implemented yet: %w", k6error.ErrFatal)
+	w.logger.Debugf("Worker:EvaluateHandle", "sid:%v tid:%v url:%q", w.session.ID(), w.targetID, w.url)
+
+	ec, err := w.executionContext()
+	if err != nil {
+		return nil, fmt.Errorf("evaluating handle in worker: %w", err)
+	}
+	h, err := ec.EvalHandle(w.ctx, pageFunc, args...)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating handle in worker: %w", err)
+	}
+
+	return h, nil
 }
 
 // URL returns the URL of the web worker.
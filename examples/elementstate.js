@@ -17,13 +17,13 @@ export const options = {
   }
 }
 
-export default function() {
+export default async function() {
   const browser = chromium.launch();
   const context = browser.newContext();
   const page = context.newPage();
 
   // Inject page content
-  page.setContent(`
+  await page.setContent(`
     <div class="visible">Hello world</div>
     <div style="display:none" class="hidden"></div>
     <div class="editable" editable>Edit me</div>
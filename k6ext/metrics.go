@@ -13,11 +13,50 @@ const (
 	webVitalCLS  = "CLS"
 	webVitalINP  = "INP"
 	webVitalFCP  = "FCP"
+
+	navigationTimingDOMContentLoaded = "domContentLoaded"
+	navigationTimingDOMInteractive   = "domInteractive"
+	navigationTimingFirstPaint       = "firstPaint"
+	navigationTimingLoad             = "load"
+
+	pageMetricJSHeapUsedSize      = "JSHeapUsedSize"
+	pageMetricNodes               = "Nodes"
+	pageMetricLayoutCount         = "LayoutCount"
+	pageMetricRecalcStyleDuration = "RecalcStyleDuration"
+	pageMetricScriptDuration      = "ScriptDuration"
+	pageMetricTaskDuration        = "TaskDuration"
 )
 
 // CustomMetrics are the custom k6 metrics used by xk6-browser.
 type CustomMetrics struct {
 	WebVitals map[string]*k6metrics.Metric
+
+	// NavigationTimings are the timings of the navigations of the pages
+	// from the Performance API, keyed by their names in the binding payload.
+	NavigationTimings map[string]*k6metrics.Metric
+	UserTiming        *k6metrics.Metric
+
+	LongTasks        *k6metrics.Metric
+	LongTaskDuration *k6metrics.Metric
+	// TotalBlockingTime is the total blocking time of the navigations,
+	// measured from the first contentful paint until the load event.
+	TotalBlockingTime *k6metrics.Metric
+
+	// PageMetrics are the sampled renderer metrics of the pages,
+	// keyed by their names in the CDP Performance domain.
+	PageMetrics map[string]*k6metrics.Metric
+
+	// ProcessCPU and ProcessMemoryRSS are sampled from
+	// the process tree of the browser on Linux.
+	ProcessCPU       *k6metrics.Metric
+	ProcessMemoryRSS *k6metrics.Metric
+
+	RequestsBlocked *k6metrics.Metric
+
+	SSEStreams          *k6metrics.Metric
+	SSEMessagesReceived *k6metrics.Metric
+	SSEMessageLatency   *k6metrics.Metric
+	SSEMessageInterval  *k6metrics.Metric
 }
 
 // RegisterCustomMetrics creates and registers our custom metrics with the k6
@@ -51,8 +90,52 @@ func RegisterCustomMetrics(registry *k6metrics.Registry) *CustomMetrics {
 			v+"_poor", k6metrics.Counter)
 	}
 
+	nts := map[string]string{
+		navigationTimingDOMContentLoaded: "browser_dom_content_loaded",
+		navigationTimingDOMInteractive:   "browser_dom_interactive",
+		navigationTimingFirstPaint:       "browser_first_paint",
+		navigationTimingLoad:             "browser_load",
+	}
+	navigationTimings := make(map[string]*k6metrics.Metric)
+	for k, v := range nts {
+		navigationTimings[k] = registry.MustNewMetric(v, k6metrics.Trend, k6metrics.Time)
+	}
+
+	pageMetrics := map[string]*k6metrics.Metric{
+		pageMetricJSHeapUsedSize: registry.MustNewMetric(
+			"browser_page_js_heap_used_size", k6metrics.Gauge, k6metrics.Data),
+		pageMetricNodes: registry.MustNewMetric(
+			"browser_page_nodes", k6metrics.Gauge),
+		pageMetricLayoutCount: registry.MustNewMetric(
+			"browser_page_layout_count", k6metrics.Gauge),
+		pageMetricRecalcStyleDuration: registry.MustNewMetric(
+			"browser_page_recalc_style_duration", k6metrics.Gauge, k6metrics.Time),
+		pageMetricScriptDuration: registry.MustNewMetric(
+			"browser_page_script_duration", k6metrics.Gauge, k6metrics.Time),
+		pageMetricTaskDuration: registry.MustNewMetric(
+			"browser_page_task_duration", k6metrics.Gauge, k6metrics.Time),
+	}
+
 	return &CustomMetrics{
-		WebVitals: webVitals,
+		WebVitals:         webVitals,
+		NavigationTimings: navigationTimings,
+		UserTiming:        registry.MustNewMetric("browser_user_timing", k6metrics.Trend, k6metrics.Time),
+		// main thread blocking
+		LongTasks:         registry.MustNewMetric("browser_long_tasks", k6metrics.Counter),
+		LongTaskDuration:  registry.MustNewMetric("browser_long_task_duration", k6metrics.Trend, k6metrics.Time),
+		TotalBlockingTime: registry.MustNewMetric("browser_tbt", k6metrics.Trend, k6metrics.Time),
+		PageMetrics:       pageMetrics,
+		RequestsBlocked:   registry.MustNewMetric("browser_requests_blocked", k6metrics.Counter),
+
+		// browser process
+		ProcessCPU:       registry.MustNewMetric("browser_process_cpu", k6metrics.Gauge),
+		ProcessMemoryRSS: registry.MustNewMetric("browser_process_memory_rss", k6metrics.Gauge, k6metrics.Data),
+
+		// server-sent events
+		SSEStreams:          registry.MustNewMetric("browser_sse_streams", k6metrics.Counter),
+		SSEMessagesReceived: registry.MustNewMetric("browser_sse_msgs_received", k6metrics.Counter),
+		SSEMessageLatency:   registry.MustNewMetric("browser_sse_msg_latency", k6metrics.Trend, k6metrics.Time),
+		SSEMessageInterval:  registry.MustNewMetric("browser_sse_msg_interval", k6metrics.Trend, k6metrics.Time),
 	}
 }
 
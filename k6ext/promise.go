@@ -36,10 +36,14 @@ func promise(ctx context.Context, fn PromisifiedFunc, d eventLoopDirective) *goj
 		vu                 = GetVU(ctx)
 		cb                 = vu.RegisterCallback()
 		p, resolve, reject = vu.Runtime().NewPromise()
+		// The passive task queues, such as the ones of the route and the
+		// event handlers, run their tasks while the VU waits on the promise.
+		waitDone = waitOn(vu)
 	)
 	go func() {
 		v, err := fn()
 		cb(func() error {
+			defer waitDone()
 			if err != nil {
 				reject(err)
 			} else {
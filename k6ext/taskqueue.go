@@ -0,0 +1,274 @@
+package k6ext
+
+import (
+	"sync"
+
+	k6modules "go.k6.io/k6/js/modules"
+)
+
+// TaskQueue runs the tasks queued from any goroutine on the event loop of
+// a VU, one after the other in the order they were queued.
+//
+// A queue returned by NewTaskQueue keeps the event loop, and so the
+// iteration, alive until it's closed. A queue returned by NewPassiveTaskQueue
+// doesn't. It runs the tasks only while the VU waits on a promise of the
+// extension, and buffers them otherwise. It's closed when the iteration
+// it was created in ends.
+type TaskQueue struct {
+	register func() func(func() error)
+	// loop is the event loop of the VU of a passive queue.
+	loop *vuLoop
+	// iteration is the iteration a passive queue was created in.
+	iteration int64
+
+	mu sync.Mutex
+	// enqueue is the registered callback of the queue that is not
+	// used yet. It's nil if the queue doesn't hold the event loop.
+	enqueue   func(func() error)
+	holding   bool
+	tasks     []func() error
+	scheduled bool
+	closed    bool
+	done      chan struct{}
+}
+
+// NewTaskQueue returns a new task queue for the VU.
+// It must be called on the event loop.
+func NewTaskQueue(vu k6modules.VU) *TaskQueue {
+	return &TaskQueue{
+		register: vu.RegisterCallback,
+		enqueue:  vu.RegisterCallback(),
+		holding:  true,
+		done:     make(chan struct{}),
+	}
+}
+
+// NewPassiveTaskQueue returns a new task queue for the VU that doesn't keep
+// the event loop alive. It's for the handlers of the events that happen on
+// their own, such as the route and the page event handlers, so that they
+// don't keep the iteration from ending if the page is left open.
+// It must be called on the event loop.
+func NewPassiveTaskQueue(vu k6modules.VU) *TaskQueue {
+	q := &TaskQueue{
+		register:  vu.RegisterCallback,
+		loop:      loopOf(vu),
+		iteration: iterationOf(vu),
+		done:      make(chan struct{}),
+	}
+	q.loop.add(q)
+
+	return q
+}
+
+// Queue queues the task to run on the event loop. An error returned by the
+// task aborts the iteration. It returns false if the queue is closed, in
+// which case the task is not run.
+func (q *TaskQueue) Queue(task func() error) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return false
+	}
+	q.tasks = append(q.tasks, task)
+	q.schedule()
+
+	return true
+}
+
+// schedule enqueues the run of the queued tasks if the queue holds the
+// event loop and the run is not already enqueued. It must be called with
+// the lock held.
+func (q *TaskQueue) schedule() {
+	if q.scheduled || q.enqueue == nil || len(q.tasks) == 0 {
+		return
+	}
+	q.scheduled = true
+	q.enqueue(q.run)
+	q.enqueue = nil
+}
+
+// run runs the queued tasks on the event loop. Since each registered
+// callback can be enqueued only once, it registers the next one while
+// on the event loop if the queue still holds the event loop.
+func (q *TaskQueue) run() error {
+	q.mu.Lock()
+	tasks := q.tasks
+	q.tasks = nil
+	q.scheduled = false
+	if q.holding && !q.closed {
+		q.enqueue = q.register()
+	}
+	q.mu.Unlock()
+
+	for _, task := range tasks {
+		if err := task(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hold makes the queue hold the event loop so that it runs the queued
+// tasks. It must be called on the event loop.
+func (q *TaskQueue) hold() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed || q.holding {
+		return
+	}
+	q.holding = true
+	if !q.scheduled {
+		q.enqueue = q.register()
+	}
+	q.schedule()
+}
+
+// release lets the event loop end. The tasks that are queued
+// afterwards are buffered until the queue holds the event loop again.
+func (q *TaskQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.holding = false
+	q.releaseCallback()
+}
+
+// releaseCallback enqueues the unused registered callback, if any,
+// so that it doesn't keep the event loop alive. It must be called
+// with the lock held.
+func (q *TaskQueue) releaseCallback() {
+	if q.enqueue == nil {
+		return
+	}
+	q.enqueue(func() error { return nil })
+	q.enqueue = nil
+}
+
+// Close closes the queue and lets the event loop end. The tasks that are
+// already scheduled to run still run, and the buffered tasks of a passive
+// queue are dropped. It can be called more than once, and from any goroutine.
+func (q *TaskQueue) Close() {
+	if q.loop != nil {
+		q.loop.remove(q)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.holding = false
+	if !q.scheduled {
+		q.tasks = nil
+	}
+	q.releaseCallback()
+	close(q.done)
+}
+
+// Done returns a channel that is closed when the queue is closed.
+func (q *TaskQueue) Done() <-chan struct{} {
+	return q.done
+}
+
+// vuLoops are the event loops of the VUs keyed by their runtimes.
+var vuLoops sync.Map //nolint:gochecknoglobals
+
+// vuLoop tracks the promises of the extension that a VU waits on, and makes
+// the passive task queues of the VU hold its event loop while there are any.
+type vuLoop struct {
+	mu      sync.Mutex
+	pending int
+	queues  map[*TaskQueue]struct{}
+}
+
+// loopOf returns the event loop of the VU.
+func loopOf(vu k6modules.VU) *vuLoop {
+	l, _ := vuLoops.LoadOrStore(vu.Runtime(), &vuLoop{
+		queues: make(map[*TaskQueue]struct{}),
+	})
+
+	return l.(*vuLoop) //nolint:forcetypeassert
+}
+
+// iterationOf returns the current iteration of the VU, or -1 in the init context.
+func iterationOf(vu k6modules.VU) int64 {
+	if state := vu.State(); state != nil {
+		return state.Iteration
+	}
+
+	return -1
+}
+
+func (l *vuLoop) add(q *TaskQueue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.queues[q] = struct{}{}
+	if l.pending > 0 {
+		q.hold()
+	}
+}
+
+func (l *vuLoop) remove(q *TaskQueue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.queues, q)
+}
+
+// begin makes the passive queues hold the event loop while the VU waits on
+// a promise. It closes the queues of the iterations that have ended. It must
+// be called on the event loop.
+func (l *vuLoop) begin(iteration int64) {
+	l.mu.Lock()
+	var ended []*TaskQueue
+	for q := range l.queues {
+		if q.iteration != iteration {
+			ended = append(ended, q)
+		}
+	}
+	l.mu.Unlock()
+	for _, q := range ended {
+		q.Close()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending++
+	if l.pending > 1 {
+		return
+	}
+	for q := range l.queues {
+		q.hold()
+	}
+}
+
+// end lets the passive queues release the event loop once
+// the VU waits on no more promises.
+func (l *vuLoop) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending--
+	if l.pending > 0 {
+		return
+	}
+	for q := range l.queues {
+		q.release()
+	}
+}
+
+// waitOn makes the passive task queues of the VU run their tasks until the
+// returned function is called. It must be called on the event loop.
+func waitOn(vu k6modules.VU) (done func()) {
+	l := loopOf(vu)
+	l.begin(iterationOf(vu))
+
+	return l.end
+}
@@ -0,0 +1,171 @@
+package k6ext_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/grafana/xk6-browser/k6ext"
+	"github.com/grafana/xk6-browser/k6ext/k6test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskQueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("runs_in_order", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		var got []int
+		err := vu.Loop.Start(func() error {
+			q := k6ext.NewTaskQueue(vu)
+			go func() {
+				for i := 0; i < 10; i++ {
+					i := i
+					q.Queue(func() error {
+						got = append(got, i)
+						return nil
+					})
+				}
+				q.Close()
+			}()
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, got)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		var n int
+		err := vu.Loop.Start(func() error {
+			q := k6ext.NewTaskQueue(vu)
+			var wg sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					q.Queue(func() error {
+						n++ // runs on the event loop
+						return nil
+					})
+				}()
+			}
+			go func() {
+				wg.Wait()
+				q.Close()
+			}()
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 10, n)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		var queued bool
+		err := vu.Loop.Start(func() error {
+			q := k6ext.NewTaskQueue(vu)
+			q.Close()
+			q.Close()
+			queued = q.Queue(func() error { return nil })
+			return nil
+		})
+		require.NoError(t, err)
+		assert.False(t, queued)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		errTask := errors.New("task failed")
+		err := vu.Loop.Start(func() error {
+			q := k6ext.NewTaskQueue(vu)
+			q.Queue(func() error { return errTask })
+			q.Close()
+			return nil
+		})
+		assert.ErrorIs(t, err, errTask)
+	})
+
+	t.Run("passive", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		var (
+			q   *k6ext.TaskQueue
+			ran []int
+		)
+		err := vu.Loop.Start(func() error {
+			q = k6ext.NewPassiveTaskQueue(vu)
+			// the task is buffered since the VU doesn't wait on a promise.
+			q.Queue(func() error {
+				ran = append(ran, 1)
+				return nil
+			})
+			k6ext.Promise(vu.Context(), func() (any, error) {
+				done := make(chan struct{})
+				q.Queue(func() error {
+					ran = append(ran, 2)
+					close(done)
+					return nil
+				})
+				<-done
+				return nil, nil
+			})
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, ran)
+
+		// the queue doesn't keep the event loop alive even though
+		// it's not closed, and it buffers the tasks in the meantime.
+		err = vu.Loop.Start(func() error {
+			assert.True(t, q.Queue(func() error {
+				ran = append(ran, 3)
+				return nil
+			}))
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, ran)
+	})
+
+	t.Run("passive_iteration_end", func(t *testing.T) {
+		t.Parallel()
+
+		vu := k6test.NewVU(t)
+		vu.MoveToVUContext()
+
+		var q *k6ext.TaskQueue
+		err := vu.Loop.Start(func() error {
+			q = k6ext.NewPassiveTaskQueue(vu)
+			return nil
+		})
+		require.NoError(t, err)
+
+		// the queue is closed once the VU waits on a promise in the next iteration.
+		vu.State().Iteration++
+		err = vu.Loop.Start(func() error {
+			k6ext.Promise(vu.Context(), func() (any, error) {
+				return nil, nil
+			})
+			return nil
+		})
+		require.NoError(t, err)
+		select {
+		case <-q.Done():
+		default:
+			t.Fatal("the queue of the ended iteration is not closed")
+		}
+		assert.False(t, q.Queue(func() error { return nil }))
+	})
+}
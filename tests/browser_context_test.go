@@ -1,12 +1,22 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	k6metrics "go.k6.io/k6/metrics"
 )
 
 func TestBrowserContextAddCookies(t *testing.T) {
@@ -140,6 +150,30 @@ func TestBrowserContextAddCookies(t *testing.T) {
 			];`,
 			shouldPanic: true,
 		},
+		{
+			description: "cookie_with_url_and_domain",
+			cookiesCmd: `[
+				{
+					name: "test_cookie_name",
+					value: "test_cookie_value",
+					url: "http://test.go",
+					domain: "test.go",
+				}
+			];`,
+			shouldPanic: true,
+		},
+		{
+			description: "cookie_invalid_same_site",
+			cookiesCmd: `[
+				{
+					name: "test_cookie_name",
+					value: "test_cookie_value",
+					url: "http://test.go",
+					sameSite: "lax",
+				}
+			];`,
+			shouldPanic: true,
+		},
 		{
 			description: "cookie_with_url",
 			cookiesCmd: `[
@@ -190,3 +224,330 @@ func TestBrowserContextAddCookies(t *testing.T) {
 		})
 	}
 }
+
+func TestBrowserContextRecordHAR(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	path := filepath.Join(t.TempDir(), "test.har")
+
+	bc, err := tb.NewContext(tb.toGojaValue(map[string]any{
+		"recordHar": map[string]any{
+			"path":    path,
+			"content": "embed",
+		},
+	}))
+	require.NoError(t, err)
+
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/get"), nil)
+	require.NoError(t, err)
+
+	bc.Close()
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+				Response struct {
+					Status  int64 `json:"status"`
+					Content struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(buf, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.NotEmpty(t, har.Log.Entries)
+	assert.Equal(t, tb.URL("/get"), har.Log.Entries[0].Request.URL)
+	assert.Equal(t, int64(200), har.Log.Entries[0].Response.Status)
+	assert.NotEmpty(t, har.Log.Entries[0].Response.Content.Text)
+}
+
+func TestBrowserContextProxy(t *testing.T) {
+	t.Parallel()
+
+	var (
+		proxyAuth   string
+		proxyAuthMu sync.Mutex
+	)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Proxy-Authorization") == "" {
+			w.Header().Set("Proxy-Authenticate", `Basic realm="proxy"`)
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		proxyAuthMu.Lock()
+		proxyAuth = r.Header.Get("Proxy-Authorization")
+		proxyAuthMu.Unlock()
+		_, _ = fmt.Fprintf(w, "<html><body>proxied %s</body></html>", r.URL.Host)
+	}))
+	defer proxy.Close()
+
+	tb := newTestBrowser(t)
+	bc, err := tb.NewContext(tb.toGojaValue(map[string]any{
+		"proxy": map[string]any{
+			"server":   proxy.URL,
+			"username": "user",
+			"password": "pass",
+		},
+	}))
+	require.NoError(t, err)
+
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+
+	_, err = p.Goto("http://proxied.test/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "proxied proxied.test", p.InnerText("body", nil))
+	proxyAuthMu.Lock()
+	defer proxyAuthMu.Unlock()
+	assert.Equal(t, "Basic dXNlcjpwYXNz", proxyAuth)
+}
+
+func TestBrowserContextBlockedRequests(t *testing.T) {
+	t.Parallel()
+
+	var (
+		samples   = make(chan k6metrics.SampleContainer, 1000)
+		tb        = newTestBrowser(t, withHTTPServer(), withSamplesListener(samples))
+		imgServed atomic.Bool
+	)
+	tb.withHandler("/page", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body><img src="/img.png">ok</body></html>`)
+	})
+	tb.withHandler("/img.png", func(w http.ResponseWriter, _ *http.Request) {
+		imgServed.Store(true)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	bc, err := tb.NewContext(tb.toGojaValue(map[string]any{
+		"blockedRequests": map[string]any{
+			"resourceTypes": []string{"image"},
+		},
+	}))
+	require.NoError(t, err)
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+
+	opts := tb.toGojaValue(map[string]any{"waitUntil": "networkidle"})
+	_, err = p.Goto(tb.URL("/page"), opts)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", p.InnerText("body", nil))
+	assert.False(t, imgServed.Load(), "blocked image should not be requested")
+
+	var reasons []string
+	for len(samples) > 0 {
+		for _, s := range (<-samples).GetSamples() {
+			if s.Metric.Name != "browser_requests_blocked" {
+				continue
+			}
+			reason, _ := s.Tags.Get("reason")
+			reasons = append(reasons, reason)
+		}
+	}
+	assert.Equal(t, []string{"resource_type"}, reasons)
+}
+
+func TestBrowserContextClearStorage(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/storage", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body>storage</body></html>`)
+	})
+
+	bc, err := tb.NewContext(nil)
+	require.NoError(t, err)
+	err = bc.ClearStorage(tb.toGojaValue(map[string]any{
+		"origins": []string{tb.URL("")},
+	}))
+	require.ErrorContains(t, err, "browser context has no pages")
+
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/storage"), nil)
+	require.NoError(t, err)
+
+	p.Evaluate(tb.toGojaValue(`() => localStorage.setItem('visited', 'yes')`))
+	require.Equal(t, "yes", p.Evaluate(tb.toGojaValue(`() => localStorage.getItem('visited')`)))
+
+	require.NoError(t, bc.ClearStorage(tb.toGojaValue(map[string]any{
+		"types": []string{"local_storage"},
+	})))
+	assert.Nil(t, p.Evaluate(tb.toGojaValue(`() => localStorage.getItem('visited')`)))
+
+	// The storage of the given origins is cleared even if no page is on them.
+	p.Evaluate(tb.toGojaValue(`() => localStorage.setItem('visited', 'yes')`))
+	_, err = p.Goto("about:blank", nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, bc.ClearStorage(nil), "no origins given")
+	require.NoError(t, bc.ClearStorage(tb.toGojaValue(map[string]any{
+		"origins": []string{tb.URL("")},
+		"types":   []string{"local_storage"},
+	})))
+	_, err = p.Goto(tb.URL("/storage"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, p.Evaluate(tb.toGojaValue(`() => localStorage.getItem('visited')`)))
+
+	err = bc.ClearStorage(tb.toGojaValue(map[string]any{
+		"types": []string{"websql"},
+	}))
+	assert.ErrorContains(t, err, `unknown storage type "websql"`)
+}
+
+func TestBrowserContextClearCache(t *testing.T) {
+	t.Parallel()
+
+	var hits atomic.Int64
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/cached", func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=3600")
+		_, _ = fmt.Fprint(w, `cached`)
+	})
+	tb.withHandler("/page", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body>page</body></html>`)
+	})
+
+	bc, err := tb.NewContext(nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, bc.ClearCache(), "browser context has no pages")
+
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/page"), nil)
+	require.NoError(t, err)
+
+	fetchCached := tb.toGojaValue(`() => fetch('/cached').then(r => r.text())`)
+	p.Evaluate(fetchCached)
+	p.Evaluate(fetchCached)
+	require.Equal(t, int64(1), hits.Load())
+
+	require.NoError(t, bc.ClearCache())
+	p.Evaluate(fetchCached)
+	assert.Equal(t, int64(2), hits.Load())
+}
+
+func TestBrowserContextStorageState(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/login", func(w http.ResponseWriter, _ *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		_, _ = fmt.Fprint(w, `<html><body><script>localStorage.setItem('token', 'xyz')</script></body></html>`)
+	})
+	tb.withHandler("/app", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `<html><body><div id="cookie">%s</div>`+
+			`<script>document.body.dataset.token = localStorage.getItem('token')</script></body></html>`, c.Value)
+	})
+
+	bc, err := tb.NewContext(nil)
+	require.NoError(t, err)
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/login"), nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "auth.json")
+	state, err := bc.StorageState(tb.toGojaValue(map[string]any{"path": path}))
+	require.NoError(t, err)
+	require.Len(t, state.Cookies, 1)
+	assert.Equal(t, "session", state.Cookies[0].Name)
+	require.Len(t, state.Origins, 1)
+	assert.Equal(t, "token", state.Origins[0].LocalStorage[0].Name)
+	_, err = os.Stat(path)
+	require.NoError(t, err)
+
+	bc2, err := tb.NewContext(tb.toGojaValue(map[string]any{"storageState": path}))
+	require.NoError(t, err)
+	p2, err := bc2.NewPage()
+	require.NoError(t, err)
+	resp, err := p2.Goto(tb.URL("/app"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(http.StatusOK), resp.Status())
+	assert.Equal(t, "abc", p2.InnerText("#cookie", nil))
+	assert.Equal(t, "xyz", p2.Evaluate(tb.toGojaValue(`() => document.body.dataset.token`)))
+
+	// the local storage is only restored on the first document of the origin.
+	require.Eventually(t, func() bool {
+		p2.Evaluate(tb.toGojaValue(`() => localStorage.removeItem('token')`))
+		if _, err := p2.Goto(tb.URL("/app"), nil); err != nil {
+			return false
+		}
+		return p2.Evaluate(tb.toGojaValue(`() => document.body.dataset.token`)) == "null"
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestBrowserContextCookies(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	bc, err := tb.NewContext(nil)
+	require.NoError(t, err)
+
+	cookies, err := tb.runJavaScript(`[
+		{ name: "root", value: "1", domain: "example.com", path: "/", sameSite: "Lax" },
+		{ name: "admin", value: "2", domain: "example.com", path: "/admin", httpOnly: true },
+		{ name: "other", value: "3", url: "https://other.com/" },
+	];`)
+	require.NoError(t, err)
+	bc.AddCookies(cookies)
+
+	cookieNames := func(urls ...string) []string {
+		t.Helper()
+
+		cc, err := bc.Cookies(urls...)
+		require.NoError(t, err)
+		names := make([]string, 0, len(cc))
+		for _, c := range cc {
+			names = append(names, c.Name)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"root", "admin", "other"}, cookieNames())
+	assert.ElementsMatch(t, []string{"root"}, cookieNames("https://example.com/"))
+	assert.ElementsMatch(t, []string{"root", "admin"}, cookieNames("https://example.com/admin"))
+	assert.ElementsMatch(t, []string{"root", "other"}, cookieNames("https://example.com/", "https://other.com/"))
+
+	cc, err := bc.Cookies("https://example.com/admin/")
+	require.NoError(t, err)
+	for _, c := range cc {
+		if c.Name == "admin" {
+			assert.True(t, c.HTTPOnly)
+		}
+		if c.Name == "root" {
+			assert.Equal(t, "Lax", c.SameSite)
+		}
+	}
+
+	bc.ClearCookies(tb.toGojaValue(map[string]any{"name": "admin"}))
+	assert.ElementsMatch(t, []string{"root", "other"}, cookieNames())
+
+	bc.ClearCookies(tb.toGojaValue(map[string]any{"domain": "other.com"}))
+	assert.ElementsMatch(t, []string{"root"}, cookieNames())
+
+	bc.ClearCookies(nil)
+	assert.Empty(t, cookieNames())
+
+	// the cookies are deleted through a page if the browser context has any.
+	bc.AddCookies(cookies)
+	_, err = bc.NewPage()
+	require.NoError(t, err)
+	bc.ClearCookies(tb.toGojaValue(map[string]any{"name": "admin", "path": "/admin"}))
+	assert.ElementsMatch(t, []string{"root", "other"}, cookieNames())
+}
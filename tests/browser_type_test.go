@@ -1,8 +1,12 @@
 package tests
 
 import (
+	"fmt"
+	"net/http"
+	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/xk6-browser/browser"
@@ -56,3 +60,36 @@ func TestBrowserTypeLaunchToConnect(t *testing.T) {
 	// to pid registry
 	require.Len(t, root.PidRegistry.Pids(), 0)
 }
+
+func TestBrowserTypeLaunchPersistentContext(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/store", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body><script>localStorage.setItem('token', 'xyz')</script></body></html>`)
+	})
+	tb.withHandler("/read", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body></body></html>`)
+	})
+
+	dir := t.TempDir()
+	bctx, pid := tb.browserType.LaunchPersistentContext(dir, nil)
+	require.NotZero(t, pid)
+	p, err := bctx.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/store"), nil)
+	require.NoError(t, err)
+	bctx.Close()
+
+	// the user data directory is kept after the browser exits.
+	_, err = os.Stat(dir)
+	require.NoError(t, err)
+
+	bctx, _ = tb.browserType.LaunchPersistentContext(dir, nil)
+	defer bctx.Close()
+	p, err = bctx.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/read"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", p.Evaluate(tb.toGojaValue(`() => localStorage.getItem('token')`)))
+}
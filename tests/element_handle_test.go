@@ -57,7 +57,7 @@ var htmlInputButton = fmt.Sprintf(`
 func TestElementHandleBoundingBoxInvisibleElement(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`<div style="display:none">hello</div>`, nil)
+	require.NoError(t, p.SetContent(`<div style="display:none">hello</div>`, nil))
 	element, err := p.Query("div")
 	require.NoError(t, err)
 	require.Nil(t, element.BoundingBox())
@@ -67,11 +67,11 @@ func TestElementHandleBoundingBoxSVG(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
 
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<svg xmlns="http://www.w3.org/2000/svg" width="500" height="500">
 			<rect id="theRect" x="30" y="50" width="200" height="300"></rect>
 		</svg>
-	`, nil)
+	`, nil))
 
 	element, err := p.Query("#therect")
 	require.NoError(t, err)
@@ -94,7 +94,7 @@ func TestElementHandleClick(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
 
-	p.SetContent(htmlInputButton, nil)
+	require.NoError(t, p.SetContent(htmlInputButton, nil))
 
 	button, err := p.Query("button")
 	require.NoError(t, err)
@@ -116,7 +116,7 @@ func TestElementHandleClickWithNodeRemoved(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
 
-	p.SetContent(htmlInputButton, nil)
+	require.NoError(t, p.SetContent(htmlInputButton, nil))
 
 	// Remove all nodes
 	p.Evaluate(tb.toGojaValue("() => delete window['Node']"))
@@ -141,7 +141,7 @@ func TestElementHandleClickWithDetachedNode(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
 
-	p.SetContent(htmlInputButton, nil)
+	require.NoError(t, p.SetContent(htmlInputButton, nil))
 	button, err := p.Query("button")
 	require.NoError(t, err)
 
@@ -220,9 +220,9 @@ func TestElementHandleGetAttribute(t *testing.T) {
 	const want = "https://somewhere"
 
 	p := newTestBrowser(t).NewPage(nil)
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<a id="dark-mode-toggle-X" href="https://somewhere">Dark</a>
-	`, nil)
+	`, nil))
 
 	el, err := p.Query("#dark-mode-toggle-X")
 	require.NoError(t, err)
@@ -234,11 +234,11 @@ func TestElementHandleGetAttribute(t *testing.T) {
 func TestElementHandleInputValue(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<input value="hello1">
 		<select><option value="hello2" selected></option></select>
 		<textarea>hello3</textarea>
-    	`, nil)
+    	`, nil))
 
 	element, err := p.Query("input")
 	require.NoError(t, err)
@@ -265,14 +265,14 @@ func TestElementHandleInputValue(t *testing.T) {
 func TestElementHandleIsChecked(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`<input type="checkbox" checked>`, nil)
+	require.NoError(t, p.SetContent(`<input type="checkbox" checked>`, nil))
 	element, err := p.Query("input")
 	require.NoError(t, err)
 
 	assert.True(t, element.IsChecked(), "expected checkbox to be checked")
 	element.Dispose()
 
-	p.SetContent(`<input type="checkbox">`, nil)
+	require.NoError(t, p.SetContent(`<input type="checkbox">`, nil))
 	element, err = p.Query("input")
 	require.NoError(t, err)
 	assert.False(t, element.IsChecked(), "expected checkbox to be unchecked")
@@ -286,12 +286,12 @@ func TestElementHandleQueryAll(t *testing.T) {
 	)
 
 	p := newTestBrowser(t).NewPage(nil)
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<ul id="aul">
 			<li class="ali">1</li>
 			<li class="ali">2</li>
 		</ul>
-  	`, nil)
+  	`, nil))
 
 	t.Run("element_handle", func(t *testing.T) {
 		el, err := p.Query("#aul")
@@ -366,7 +366,7 @@ func TestElementHandleScreenshot(t *testing.T) {
 func TestElementHandleWaitForSelector(t *testing.T) {
 	tb := newTestBrowser(t)
 	p := tb.NewPage(nil)
-	p.SetContent(`<div class="root"></div>`, nil)
+	require.NoError(t, p.SetContent(`<div class="root"></div>`, nil))
 
 	root, err := p.Query(".root")
 	require.NoError(t, err)
@@ -396,7 +396,7 @@ func TestElementHandlePress(t *testing.T) {
 
 	p := tb.NewPage(nil)
 
-	p.SetContent(`<input>`, nil)
+	require.NoError(t, p.SetContent(`<input>`, nil))
 
 	el, err := p.Query("input")
 	require.NoError(t, err)
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
+)
+
+func TestBrowserExtensionWorkers(t *testing.T) {
+	t.Parallel()
+
+	ext := t.TempDir()
+	manifest := `{
+		"name": "test",
+		"version": "1.0",
+		"manifest_version": 3,
+		"background": { "service_worker": "background.js" }
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(ext, "manifest.json"), []byte(manifest), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(ext, "background.js"), []byte(`self.answer = 42;`), 0o600))
+
+	opts := defaultBrowserOpts()
+	opts.Extensions = []string{ext}
+	tb := newTestBrowser(t, withBrowserOptions(opts))
+
+	bctx, pid := tb.browserType.LaunchPersistentContext(t.TempDir(), nil)
+	require.NotZero(t, pid)
+	defer bctx.Close()
+
+	var workers []api.Worker
+	require.Eventually(t, func() bool {
+		workers = bctx.ExtensionWorkers()
+		return len(workers) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Contains(t, workers[0].URL(), "chrome-extension://")
+	assert.Equal(t, int64(42), workers[0].Evaluate(tb.toGojaValue(`() => self.answer`)))
+}
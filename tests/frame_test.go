@@ -14,7 +14,7 @@ func TestFramePress(t *testing.T) {
 
 	p := tb.NewPage(nil)
 
-	p.SetContent(`<input id="text1">`, nil)
+	require.NoError(t, p.SetContent(`<input id="text1">`, nil))
 
 	f := p.Frames()[0]
 
@@ -105,6 +105,6 @@ func TestFrameNoPanicWithEmbeddedIFrame(t *testing.T) {
 
 func TestFrameTitle(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
-	p.SetContent(`<html><head><title>Some title</title></head></html>`, nil)
+	require.NoError(t, p.SetContent(`<html><head><title>Some title</title></head></html>`, nil))
 	assert.Equal(t, "Some title", p.MainFrame().Title())
 }
@@ -30,7 +30,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<input>`, nil)
+		require.NoError(t, p.SetContent(`<input>`, nil))
 		el, err := p.Query("input")
 		require.NoError(t, err)
 		p.Focus("input", nil)
@@ -46,7 +46,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<input>`, nil)
+		require.NoError(t, p.SetContent(`<input>`, nil))
 		el, err := p.Query("input")
 		require.NoError(t, err)
 		p.Focus("input", nil)
@@ -71,7 +71,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<input>`, nil)
+		require.NoError(t, p.SetContent(`<input>`, nil))
 		el, err := p.Query("input")
 		require.NoError(t, err)
 		p.Focus("input", nil)
@@ -95,7 +95,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<textarea>`, nil)
+		require.NoError(t, p.SetContent(`<textarea>`, nil))
 		el, err := p.Query("textarea")
 		require.NoError(t, err)
 		p.Focus("textarea", nil)
@@ -108,7 +108,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<textarea>`, nil)
+		require.NoError(t, p.SetContent(`<textarea>`, nil))
 		el, err := p.Query("textarea")
 		require.NoError(t, err)
 		p.Focus("textarea", nil)
@@ -133,7 +133,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<textarea>`, nil)
+		require.NoError(t, p.SetContent(`<textarea>`, nil))
 		el, err := p.Query("textarea")
 		require.NoError(t, err)
 		p.Focus("textarea", nil)
@@ -149,7 +149,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<textarea>`, nil)
+		require.NoError(t, p.SetContent(`<textarea>`, nil))
 		el, err := p.Query("textarea")
 		require.NoError(t, err)
 		p.Focus("textarea", nil)
@@ -166,7 +166,7 @@ func TestKeyboardPress(t *testing.T) {
 		p := tb.NewPage(nil)
 		kb := p.GetKeyboard()
 
-		p.SetContent(`<input>`, nil)
+		require.NoError(t, p.SetContent(`<input>`, nil))
 		el, err := p.Query("input")
 		require.NoError(t, err)
 		p.Focus("input", nil)
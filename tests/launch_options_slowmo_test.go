@@ -115,14 +115,15 @@ func TestBrowserOptionsSlowMo(t *testing.T) {
 			t.Parallel()
 			tb := newTestBrowser(t, withFileServer())
 			testPageSlowMoImpl(t, tb, func(_ *testBrowser, p api.Page) {
-				p.Reload(nil)
+				_, err := p.Reload(nil)
+				require.NoError(t, err)
 			})
 		})
 		t.Run("setContent", func(t *testing.T) {
 			t.Parallel()
 			tb := newTestBrowser(t, withFileServer())
 			testPageSlowMoImpl(t, tb, func(_ *testBrowser, p api.Page) {
-				p.SetContent("hello world", nil)
+				require.NoError(t, p.SetContent("hello world", nil))
 			})
 		})
 		/*t.Run("setInputFiles", func(t *testing.T) {
@@ -244,7 +245,7 @@ func TestBrowserOptionsSlowMo(t *testing.T) {
 			t.Parallel()
 			tb := newTestBrowser(t, withFileServer())
 			testFrameSlowMoImpl(t, tb, func(_ *testBrowser, f api.Frame) {
-				f.SetContent("hello world", nil)
+				require.NoError(t, f.SetContent("hello world", nil))
 			})
 		})
 		/*t.Run("setInputFiles", func(t *testing.T) {
@@ -304,7 +305,7 @@ func testSlowMoImpl(t *testing.T, tb *testBrowser, fn func(*testBrowser)) {
 func testPageSlowMoImpl(t *testing.T, tb *testBrowser, fn func(*testBrowser, api.Page)) {
 	p := tb.NewPage(nil)
 
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<button>a</button>
 		<input type="checkbox" class="check">
 		<input type="checkbox" checked=true class="uncheck">
@@ -313,7 +314,7 @@ func testPageSlowMoImpl(t *testing.T, tb *testBrowser, fn func(*testBrowser, api
 		<option>foo</option>
 		</select>
 		<input type="file" class="file">
-    	`, nil)
+    	`, nil))
 	testSlowMoImpl(t, tb, func(tb *testBrowser) { fn(tb, p) })
 }
 
@@ -321,7 +322,7 @@ func testFrameSlowMoImpl(t *testing.T, tb *testBrowser, fn func(bt *testBrowser,
 	p := tb.NewPage(nil)
 
 	f := tb.attachFrame(p, "frame1", tb.staticURL("empty.html"))
-	f.SetContent(`
+	require.NoError(t, f.SetContent(`
 		<button>a</button>
 		<input type="checkbox" class="check">
 		<input type="checkbox" checked=true class="uncheck">
@@ -330,6 +331,6 @@ func testFrameSlowMoImpl(t *testing.T, tb *testBrowser, fn func(bt *testBrowser,
 		  <option>foo</option>
 		</select>
 		<input type="file" class="file">
-    	`, nil)
+    	`, nil))
 	testSlowMoImpl(t, tb, func(tb *testBrowser) { fn(tb, f) })
 }
@@ -397,7 +397,8 @@ func TestLifecycleReload(t *testing.T) {
 					WaitUntil: tt.waitUntil,
 					Timeout:   30 * time.Second,
 				})
-				p.Reload(opts)
+				_, err := p.Reload(opts)
+				require.NoError(t, err)
 
 				result = p.TextContent("#pingRequestText", nil)
 				tt.pingRequestTextAssert(result, 20)
@@ -302,7 +302,7 @@ func TestLocator(t *testing.T) {
 
 			tb := newTestBrowser(t)
 			p := tb.NewPage(nil)
-			p.SetContent("<html></html>", nil)
+			require.NoError(t, p.SetContent("<html></html>", nil))
 			assert.Panics(t, func() { tt.do(p.Locator("NOTEXIST", nil), tb) })
 		})
 	}
@@ -411,7 +411,7 @@ func TestLocatorElementState(t *testing.T) {
 
 			tb := newTestBrowser(t)
 			p := tb.NewPage(nil)
-			p.SetContent("<html></html>", nil)
+			require.NoError(t, p.SetContent("<html></html>", nil))
 			assert.Panics(t, func() { tt.do(p.Locator("NOTEXIST", nil), tb) })
 		})
 	}
@@ -438,7 +438,7 @@ func TestLocatorPress(t *testing.T) {
 
 	p := tb.NewPage(nil)
 
-	p.SetContent(`<input id="text1">`, nil)
+	require.NoError(t, p.SetContent(`<input id="text1">`, nil))
 
 	l := p.Locator("#text1", nil)
 
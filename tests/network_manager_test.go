@@ -1,9 +1,13 @@
 package tests
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -115,3 +119,329 @@ func TestBasicAuth(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, int(resp.Status()))
 	})
 }
+
+func TestPageRoute(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	handler := api.RouteHandler{
+		Value: tb.toGojaValue("fulfill"),
+		Call: func(r api.Route) error {
+			r.Fulfill(tb.toGojaValue(map[string]any{
+				"status":      201,
+				"contentType": "text/plain",
+				"body":        "fulfilled",
+			}))
+			return nil
+		},
+	}
+	err := tb.runOnEventLoop(
+		func() error {
+			p.Route(tb.toGojaValue("**/get"), handler)
+			return nil
+		},
+		func() error {
+			res, err := p.Goto(tb.URL("/get"), nil)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, int64(201), res.Status())
+
+			p.Unroute(tb.toGojaValue("**/get"), nil)
+
+			res, err = p.Goto(tb.URL("/get"), nil)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, int64(200), res.Status())
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestPageReloadRoute(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	// The route handler runs on the event loop, so the reload
+	// must not block the event loop while it waits for the request.
+	var routed int
+	handler := api.RouteHandler{
+		Value: tb.toGojaValue("continue"),
+		Call: func(r api.Route) error {
+			routed++
+			r.Continue(nil)
+			return nil
+		},
+	}
+	err := tb.runOnEventLoop(
+		func() error {
+			p.Route(tb.toGojaValue("**/get"), handler)
+			return nil
+		},
+		func() error {
+			defer p.Unroute(tb.toGojaValue("**/get"), nil)
+
+			if _, err := p.Goto(tb.URL("/get"), nil); err != nil {
+				return err
+			}
+			res, err := p.Reload(tb.toGojaValue(map[string]any{"timeout": 5000}))
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, int64(200), res.Status())
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 2, routed)
+}
+
+func TestPageRouteLeftOpen(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	// The page is neither unrouted nor closed, so the route and the
+	// page event handlers must not keep the event loop alive.
+	var routed int
+	handler := api.RouteHandler{
+		Value: tb.toGojaValue("continue"),
+		Call: func(r api.Route) error {
+			routed++
+			r.Continue(nil)
+			return nil
+		},
+	}
+	err := tb.runOnEventLoop(
+		func() error {
+			p.Route(tb.toGojaValue("**/get"), handler)
+			return p.On(common.EventPageWebSocket, func(any) error { return nil })
+		},
+		func() error {
+			_, err := p.Goto(tb.URL("/get"), nil)
+			return err
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 1, routed)
+}
+
+func TestBrowserContextRoute(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	bctx, err := tb.NewContext(nil)
+	require.NoError(t, err)
+
+	var (
+		contextHandler = api.RouteHandler{
+			Value: tb.toGojaValue("abort"),
+			Call: func(r api.Route) error {
+				r.Abort("")
+				return nil
+			},
+		}
+		pageHandler = api.RouteHandler{
+			Value: tb.toGojaValue("fulfill"),
+			Call: func(r api.Route) error {
+				r.Fulfill(tb.toGojaValue(map[string]any{"status": 201}))
+				return nil
+			},
+		}
+		p api.Page
+	)
+	err = tb.runOnEventLoop(
+		func() error {
+			bctx.Route(tb.toGojaValue("**/get"), contextHandler)
+
+			// The context route applies to pages created after it was registered.
+			p, err = bctx.NewPage()
+			return err
+		},
+		func() error {
+			defer bctx.Unroute(tb.toGojaValue("**/get"), nil)
+
+			res, err := p.Goto(tb.URL("/get"), nil)
+			if err != nil {
+				return err
+			}
+			assert.Nil(t, res)
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	// Page routes take precedence over the context routes.
+	err = tb.runOnEventLoop(
+		func() error {
+			bctx.Route(tb.toGojaValue("**/get"), contextHandler)
+			p.Route(tb.toGojaValue("**/get"), pageHandler)
+			return nil
+		},
+		func() error {
+			defer bctx.Unroute(tb.toGojaValue("**/get"), nil)
+			defer p.Unroute(tb.toGojaValue("**/get"), nil)
+
+			res, err := p.Goto(tb.URL("/get"), nil)
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, int64(201), res.Status())
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestPageRouteFromHAR(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	har := fmt.Sprintf(`{"log": {"version": "1.2", "entries": [{
+		"request": {"method": "GET", "url": %q},
+		"response": {
+			"status": 200,
+			"headers": [{"name": "Content-Type", "value": "text/html"}],
+			"content": {"mimeType": "text/html", "text": "<p>from HAR</p>"}
+		}
+	}]}}`, tb.URL("/frozen"))
+	path := filepath.Join(t.TempDir(), "frozen.har")
+	require.NoError(t, os.WriteFile(path, []byte(har), 0o600))
+
+	p.RouteFromHAR(path, tb.toGojaValue(map[string]any{
+		"url":      "**/frozen",
+		"notFound": "abort",
+	}))
+
+	res, err := p.Goto(tb.URL("/frozen"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+	assert.Equal(t, int64(200), res.Status())
+	assert.Contains(t, p.Content(), "from HAR")
+}
+
+func TestPageRouteFromHARUpdate(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	path := filepath.Join(t.TempDir(), "update.har")
+
+	bctx, err := tb.NewContext(nil)
+	require.NoError(t, err)
+	p1, err := bctx.NewPage()
+	require.NoError(t, err)
+	p2, err := bctx.NewPage()
+	require.NoError(t, err)
+
+	// Only the requests of the page that routes from the HAR are recorded.
+	p1.RouteFromHAR(path, tb.toGojaValue(map[string]any{"update": true}))
+	_, err = p1.Goto(tb.URL("/get"), nil)
+	require.NoError(t, err)
+	_, err = p2.Goto(tb.URL("/html"), nil)
+	require.NoError(t, err)
+
+	bctx.Close()
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var har struct {
+		Log struct {
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(buf, &har))
+	var urls []string
+	for _, e := range har.Log.Entries {
+		urls = append(urls, e.Request.URL)
+	}
+	assert.Equal(t, []string{tb.URL("/get")}, urls)
+}
+
+func TestRequestRedirectChain(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	res, err := p.Goto(tb.URL("/redirect/2"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, res)
+
+	req := res.Request()
+	assert.Equal(t, tb.URL("/get"), req.URL())
+	assert.Nil(t, req.RedirectedTo())
+
+	var chain []string
+	for r := req.RedirectedFrom(); r != nil; r = r.RedirectedFrom() {
+		chain = append(chain, r.URL())
+		assert.NotNil(t, r.RedirectedTo())
+	}
+	assert.Equal(t, []string{
+		tb.URL("/relative-redirect/1"),
+		tb.URL("/redirect/2"),
+	}, chain)
+}
+
+func TestPageEventSource(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/stream", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = fmt.Fprint(w, "event: update\nid: 1\ndata: hello\n\n")
+		w.(http.Flusher).Flush() //nolint:forcetypeassert
+	})
+	p := tb.NewPage(nil)
+
+	var (
+		msgs       []*common.EventSourceMessage
+		receivedCh = make(chan struct{}, 1)
+	)
+	err := tb.runOnEventLoop(
+		func() error {
+			return p.On("eventsource", func(data any) error {
+				msgs = append(msgs, data.(*common.EventSourceMessage)) //nolint:forcetypeassert
+				select {
+				case receivedCh <- struct{}{}:
+				default:
+				}
+				return nil
+			})
+		},
+		func() error {
+			if _, err := p.Goto(tb.URL("/get"), nil); err != nil {
+				return err
+			}
+			p.Evaluate(tb.toGojaValue(`url => { new EventSource(url) }`), tb.toGojaValue(tb.URL("/stream")))
+
+			select {
+			case <-receivedCh:
+			case <-time.After(5 * time.Second):
+			}
+			return p.Close(nil)
+		},
+	)
+	require.NoError(t, err)
+	require.NotEmpty(t, msgs, "should handle the eventsource event")
+	assert.Equal(t, tb.URL("/stream"), msgs[0].URL)
+	assert.Equal(t, "update", msgs[0].EventName)
+	assert.Equal(t, "1", msgs[0].EventID)
+	assert.Equal(t, "hello", msgs[0].Data)
+}
@@ -6,11 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"image/png"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
+
+	k6metrics "go.k6.io/k6/metrics"
 )
 
 type emulateMediaOpts struct {
@@ -59,7 +65,7 @@ func TestPageContent(t *testing.T) {
 	p := tb.NewPage(nil)
 
 	content := `<!DOCTYPE html><html><head></head><body><h1>Hello</h1></body></html>`
-	p.SetContent(content, nil)
+	require.NoError(t, p.SetContent(content, nil))
 
 	assert.Equal(t, content, p.Content())
 }
@@ -201,7 +207,7 @@ func TestPageInnerHTML(t *testing.T) {
 		t.Parallel()
 
 		p := newTestBrowser(t).NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		assert.Equal(t, `<b>Test</b><ol><li><i>One</i></li></ol>`, p.InnerHTML("div", nil))
 	})
 
@@ -223,7 +229,7 @@ func TestPageInnerHTML(t *testing.T) {
 
 		tb := newTestBrowser(t)
 		p := tb.NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		require.Panics(t, func() { p.InnerHTML("p", tb.toGojaValue(jsFrameBaseOpts{Timeout: "100"})) })
 	})
 }
@@ -235,7 +241,7 @@ func TestPageInnerText(t *testing.T) {
 		t.Parallel()
 
 		p := newTestBrowser(t).NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		assert.Equal(t, "Test\nOne", p.InnerText("div", nil))
 	})
 
@@ -254,7 +260,7 @@ func TestPageInnerText(t *testing.T) {
 
 		tb := newTestBrowser(t)
 		p := tb.NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		require.Panics(t, func() { p.InnerText("p", tb.toGojaValue(jsFrameBaseOpts{Timeout: "100"})) })
 	})
 }
@@ -266,7 +272,7 @@ func TestPageTextContent(t *testing.T) {
 		t.Parallel()
 
 		p := newTestBrowser(t).NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		assert.Equal(t, "TestOne", p.TextContent("div", nil))
 	})
 
@@ -285,7 +291,7 @@ func TestPageTextContent(t *testing.T) {
 
 		tb := newTestBrowser(t)
 		p := tb.NewPage(nil)
-		p.SetContent(sampleHTML, nil)
+		require.NoError(t, p.SetContent(sampleHTML, nil))
 		require.Panics(t, func() { p.TextContent("p", tb.toGojaValue(jsFrameBaseOpts{Timeout: "100"})) })
 	})
 }
@@ -293,11 +299,11 @@ func TestPageTextContent(t *testing.T) {
 func TestPageInputValue(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<input value="hello1">
 		<select><option value="hello2" selected></option></select>
 		<textarea>hello3</textarea>
-     	`, nil)
+     	`, nil))
 
 	got, want := p.InputValue("input", nil), "hello1"
 	assert.Equal(t, got, want)
@@ -313,7 +319,7 @@ func TestPageInputValue(t *testing.T) {
 func TestPageInputSpecialCharacters(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`<input id="special">`, nil)
+	require.NoError(t, p.SetContent(`<input id="special">`, nil))
 	el, err := p.Query("#special")
 	require.NoError(t, err)
 
@@ -335,12 +341,12 @@ func TestPageInputSpecialCharacters(t *testing.T) {
 
 func TestPageFill(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
-	p.SetContent(`
+	require.NoError(t, p.SetContent(`
 		<input id="text" type="text" value="something" />
 		<input id="date" type="date" value="2012-03-12"/>
 		<input id="number" type="number" value="42"/>
 		<input id="unfillable" type="radio" />
-	`, nil)
+	`, nil))
 
 	happy := []struct{ name, selector, value string }{
 		{name: "text", selector: "#text", value: "fill me up"},
@@ -368,10 +374,10 @@ func TestPageFill(t *testing.T) {
 func TestPageIsChecked(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
 
-	p.SetContent(`<input type="checkbox" checked>`, nil)
+	require.NoError(t, p.SetContent(`<input type="checkbox" checked>`, nil))
 	assert.True(t, p.IsChecked("input", nil), "expected checkbox to be checked")
 
-	p.SetContent(`<input type="checkbox">`, nil)
+	require.NoError(t, p.SetContent(`<input type="checkbox">`, nil))
 	assert.False(t, p.IsChecked("input", nil), "expected checkbox to be unchecked")
 }
 
@@ -420,7 +426,7 @@ func TestPageScreenshotFullpage(t *testing.T) {
 
 func TestPageTitle(t *testing.T) {
 	p := newTestBrowser(t).NewPage(nil)
-	p.SetContent(`<html><head><title>Some title</title></head></html>`, nil)
+	require.NoError(t, p.SetContent(`<html><head><title>Some title</title></head></html>`, nil))
 	assert.Equal(t, "Some title", p.Title())
 }
 
@@ -657,7 +663,7 @@ func TestPagePress(t *testing.T) {
 
 	p := tb.NewPage(nil)
 
-	p.SetContent(`<input id="text1">`, nil)
+	require.NoError(t, p.SetContent(`<input id="text1">`, nil))
 
 	p.Press("#text1", "Shift+KeyA", nil)
 	p.Press("#text1", "KeyB", nil)
@@ -715,3 +721,168 @@ func assertExceptionContains(t *testing.T, rt *goja.Runtime, fn func(), expErrMs
 	_, err := cal(goja.Undefined())
 	require.ErrorContains(t, err, expErrMsg)
 }
+
+func TestPageWaitForRequestAndResponse(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	waitForRequest, err := p.WaitForRequest(api.RequestMatcher{URL: tb.toGojaValue("**/get")}, nil)
+	require.NoError(t, err)
+	waitForResponse, err := p.WaitForResponse(api.ResponseMatcher{URL: tb.toGojaValue(tb.URL("/get"))}, nil)
+	require.NoError(t, err)
+
+	_, err = p.Goto(tb.URL("/get"), nil)
+	require.NoError(t, err)
+
+	req, err := waitForRequest()
+	require.NoError(t, err)
+	assert.Equal(t, tb.URL("/get"), req.URL())
+
+	resp, err := waitForResponse()
+	require.NoError(t, err)
+	assert.Equal(t, int64(http.StatusOK), resp.Status())
+}
+
+func TestPageWaitForResponsePredicate(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	p := tb.NewPage(nil)
+
+	var (
+		waitForResponse func() (api.Response, error)
+		calls           int
+	)
+	err := tb.runOnEventLoop(
+		func() error {
+			var err error
+			waitForResponse, err = p.WaitForResponse(api.ResponseMatcher{
+				Predicate: func(resp api.Response) (bool, error) {
+					calls++ // runs on the event loop
+					return resp.Status() == http.StatusOK, nil
+				},
+			}, nil)
+			return err
+		},
+		func() error {
+			if _, err := p.Goto(tb.URL("/get"), nil); err != nil {
+				return err
+			}
+			resp, err := waitForResponse()
+			if err != nil {
+				return err
+			}
+			assert.Equal(t, tb.URL("/get"), resp.URL())
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Positive(t, calls)
+}
+
+func TestPageWaitForRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+
+	waitForRequest, err := p.WaitForRequest(api.RequestMatcher{URL: tb.toGojaValue("**/never")}, tb.toGojaValue(map[string]any{
+		"timeout": 100,
+	}))
+	require.NoError(t, err)
+	_, err = waitForRequest()
+	require.ErrorContains(t, err, "timed out after 100ms")
+}
+
+func TestPageIndexedDB(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	tb.withHandler("/app", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body></body></html>`)
+	})
+
+	p := tb.NewPage(nil)
+	_, err := p.Goto(tb.URL("/app"), nil)
+	require.NoError(t, err)
+
+	db := p.GetIndexedDB()
+	entries, err := tb.runJavaScript(`JSON.stringify([
+		{ value: { id: 1, name: "a" } },
+		{ value: { id: 2, name: "b" } },
+		{ value: { id: 3, name: "c" } },
+	])`)
+	require.NoError(t, err)
+	err = db.Seed("app", "todos", entries, tb.toGojaValue(map[string]any{"keyPath": "id"}))
+	require.NoError(t, err)
+
+	names, err := db.Databases()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app"}, names)
+
+	d, err := db.Database("app")
+	require.NoError(t, err)
+	require.Len(t, d.ObjectStores, 1)
+	assert.Equal(t, "todos", d.ObjectStores[0].Name)
+	assert.Equal(t, "id", d.ObjectStores[0].KeyPath)
+
+	got, err := db.Entries("app", "todos", tb.toGojaValue(map[string]any{"lower": 2}))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, float64(2), got[0].Key)
+	assert.Equal(t, map[string]any{"id": float64(2), "name": "b"}, got[0].Value)
+
+	got, err = db.Entries("app", "todos", tb.toGojaValue(map[string]any{"limit": 1}))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.NoError(t, db.Clear("app", "todos"))
+	got, err = db.Entries("app", "todos", nil)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestPageMetrics(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t)
+	p := tb.NewPage(nil)
+	require.NoError(t, p.SetContent(`<div>metrics</div>`, nil))
+
+	metrics, err := p.Metrics()
+	require.NoError(t, err)
+	for _, name := range []string{
+		"JSHeapUsedSize", "Nodes", "LayoutCount",
+		"RecalcStyleDuration", "ScriptDuration", "TaskDuration",
+	} {
+		assert.Contains(t, metrics, name)
+	}
+	assert.Greater(t, metrics["Nodes"], float64(0))
+}
+
+func TestPageMetricsInterval(t *testing.T) {
+	t.Parallel()
+
+	samples := make(chan k6metrics.SampleContainer)
+	tb := newTestBrowser(t, withSamplesListener(samples))
+	bctx, err := tb.NewContext(tb.toGojaValue(map[string]any{"metricsInterval": 100}))
+	require.NoError(t, err)
+	_, err = bctx.NewPage()
+	require.NoError(t, err)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case sc := <-samples:
+			for _, s := range sc.GetSamples() {
+				if s.Metric.Name == "browser_page_nodes" {
+					return
+				}
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for the page metrics")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/xk6-browser/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withServiceWorkerHandlers(tb *testBrowser) {
+	tb.withHandler("/sw", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body>sw</body></html>`)
+	})
+	tb.withHandler("/sw.js", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		_, _ = fmt.Fprint(w, `self.answer = 42;`)
+	})
+}
+
+func TestBrowserContextServiceWorkers(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	withServiceWorkerHandlers(tb)
+
+	bc, err := tb.NewContext(nil)
+	require.NoError(t, err)
+
+	workers := make(chan api.Worker, 1)
+	err = tb.runOnEventLoop(
+		func() error {
+			return bc.On("serviceworker", func(data any) error {
+				w, ok := data.(api.Worker)
+				require.True(t, ok)
+				select {
+				case workers <- w:
+				default:
+				}
+				return nil
+			})
+		},
+		func() error {
+			defer bc.Close()
+
+			p, err := bc.NewPage()
+			if err != nil {
+				return err
+			}
+			if _, err := p.Goto(tb.URL("/sw"), nil); err != nil {
+				return err
+			}
+			p.Evaluate(tb.toGojaValue(`() => navigator.serviceWorker.register('/sw.js').then(() => navigator.serviceWorker.ready)`))
+
+			var w api.Worker
+			select {
+			case w = <-workers:
+			case <-time.After(5 * time.Second):
+				return errors.New("timed out waiting for the serviceworker event")
+			}
+			assert.Equal(t, tb.URL("/sw.js"), w.URL())
+			assert.Equal(t, int64(42), w.Evaluate(tb.toGojaValue(`() => self.answer`)))
+			assert.Len(t, bc.ServiceWorkers(), 1)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+}
+
+func TestBrowserContextServiceWorkersBlock(t *testing.T) {
+	t.Parallel()
+
+	tb := newTestBrowser(t, withHTTPServer())
+	withServiceWorkerHandlers(tb)
+
+	bc, err := tb.NewContext(tb.toGojaValue(map[string]any{
+		"serviceWorkers": "block",
+	}))
+	require.NoError(t, err)
+
+	p, err := bc.NewPage()
+	require.NoError(t, err)
+	_, err = p.Goto(tb.URL("/sw"), nil)
+	require.NoError(t, err)
+
+	rejection := p.Evaluate(tb.toGojaValue(`() => navigator.serviceWorker.register('/sw.js').then(
+		() => 'registered',
+		e => e instanceof DOMException ? e.name : String(e),
+	)`))
+	assert.Equal(t, "SecurityError", rejection)
+	assert.Empty(t, bc.ServiceWorkers())
+}
@@ -298,6 +298,31 @@ func (b *testBrowser) run(ctx context.Context, fs ...func() error) error { //nol
 	return g.Wait()
 }
 
+// runOnEventLoop runs setup on the event loop of the VU, then runs fn in a
+// promise, the same as the async methods of the browser module, while the
+// event loop runs the callbacks queued to it, such as the route and the
+// event handlers. It returns once fn and the event loop are done.
+func (b *testBrowser) runOnEventLoop(setup func() error, fn func() error) error {
+	b.t.Helper()
+
+	var fnErr error
+	err := b.vu.Loop.Start(func() error {
+		if err := setup(); err != nil {
+			return err
+		}
+		k6ext.Promise(b.vu.Context(), func() (any, error) {
+			fnErr = fn()
+			return nil, nil
+		})
+		return nil
+	})
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return fnErr
+}
+
 // awaitWithTimeout is the same as await but takes a timeout and times out the function after the time runs out.
 func (b *testBrowser) awaitWithTimeout(timeout time.Duration, fn func() error) error {
 	b.t.Helper()
@@ -392,10 +417,11 @@ func (t testPromise) then(resolve any, reject ...any) testPromise {
 // browserOptions provides a way to customize browser
 // options in tests.
 type browserOptions struct {
-	Args     []string `js:"args"`
-	Debug    bool     `js:"debug"`
-	Headless bool     `js:"headless"`
-	Timeout  string   `js:"timeout"`
+	Args       []string `js:"args"`
+	Debug      bool     `js:"debug"`
+	Extensions []string `js:"extensions"`
+	Headless   bool     `js:"headless"`
+	Timeout    string   `js:"timeout"`
 }
 
 // withBrowserOptions is a helper for increasing readability
@@ -508,6 +534,10 @@ func setupEnvLookupper(tb testing.TB, opts browserOptions) env.LookupFunc {
 			}
 		case "K6_BROWSER_DEBUG":
 			return strconv.FormatBool(opts.Debug), true
+		case "K6_BROWSER_EXTENSIONS":
+			if len(opts.Extensions) != 0 {
+				return strings.Join(opts.Extensions, ","), true
+			}
 		case "K6_BROWSER_HEADLESS":
 			return strconv.FormatBool(opts.Headless), true
 		case "K6_BROWSER_TIMEOUT":
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/xk6-browser/api"
+	"github.com/grafana/xk6-browser/common"
+
+	k6metrics "go.k6.io/k6/metrics"
+)
+
+func TestPageWebSocket(t *testing.T) {
+	t.Parallel()
+
+	var (
+		samples = make(chan k6metrics.SampleContainer, 1000)
+		tb      = newTestBrowser(t, withHTTPServer(), withSamplesListener(samples))
+		p       = tb.NewPage(nil)
+	)
+	tb.withHandler("/echo", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close() //nolint:errcheck
+		mt, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.WriteMessage(mt, msg)
+	})
+
+	var (
+		ws         api.WebSocket
+		payloads   []string
+		receivedCh = make(chan struct{}, 1)
+		wsURL      = strings.Replace(tb.URL("/echo"), "http://", "ws://", 1)
+	)
+	err := tb.runOnEventLoop(
+		func() error {
+			return p.On("websocket", func(data any) error {
+				ws = data.(api.WebSocket) //nolint:forcetypeassert
+				return ws.On("framereceived", func(data any) error {
+					payloads = append(payloads, data.(*common.WebSocketFrame).Payload) //nolint:forcetypeassert
+					select {
+					case receivedCh <- struct{}{}:
+					default:
+					}
+					return nil
+				})
+			})
+		},
+		func() error {
+			p.Evaluate(tb.toGojaValue(`url => {
+				const ws = new WebSocket(url);
+				ws.onopen = () => ws.send("hello");
+				ws.onmessage = () => ws.close();
+			}`), tb.toGojaValue(wsURL))
+
+			select {
+			case <-receivedCh:
+			case <-time.After(5 * time.Second):
+			}
+			return p.Close(nil)
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, ws, "should handle the websocket event")
+	assert.Equal(t, wsURL, ws.URL())
+	assert.Equal(t, []string{"hello"}, payloads)
+
+	seen := make(map[string]bool)
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case sc := <-samples:
+				for _, s := range sc.GetSamples() {
+					seen[s.Metric.Name] = true
+				}
+			default:
+				return seen["ws_sessions"] && seen["ws_msgs_sent"] &&
+					seen["ws_msgs_received"] && seen["ws_session_duration"]
+			}
+		}
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -2,6 +2,8 @@ package tests
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -67,3 +69,180 @@ func TestWebVitalMetric(t *testing.T) {
 		assert.True(t, v, "expected %s to have been measured and emitted", k)
 	}
 }
+
+func TestWebVitalMetricCPUThrottlingTag(t *testing.T) {
+	var (
+		samples = make(chan k6metrics.SampleContainer)
+		browser = newTestBrowser(t, withFileServer(), withSamplesListener(samples))
+		page    = browser.NewPage(nil)
+		tagged  = make(chan string, 1)
+	)
+	page.ThrottleCPU(2)
+
+	go func() {
+		for metric := range samples {
+			for _, s := range metric.GetSamples() {
+				if s.Metric.Name != "browser_web_vital_ttfb" {
+					continue
+				}
+				rate, _ := s.Tags.Get("cpu_throttling_rate")
+				select {
+				case tagged <- rate:
+				default:
+				}
+			}
+		}
+	}()
+
+	resp, err := page.Goto(browser.staticURL("/web_vitals.html"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	select {
+	case rate := <-tagged:
+		assert.Equal(t, "2", rate)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the web vital metric")
+	}
+}
+
+// TestNavigationTimingMetrics is asserting that the navigation
+// timing metrics are emitted when navigating to a web page.
+func TestNavigationTimingMetrics(t *testing.T) {
+	var (
+		samples  = make(chan k6metrics.SampleContainer)
+		browser  = newTestBrowser(t, withFileServer(), withSamplesListener(samples))
+		page     = browser.NewPage(nil)
+		expected = map[string]bool{
+			"browser_dom_content_loaded": false,
+			"browser_dom_interactive":    false,
+			"browser_first_paint":        false,
+			"browser_load":               false,
+		}
+		navigationTypes = make(chan string, len(expected))
+	)
+
+	count := 0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	go func() {
+		for {
+			metric := <-samples
+			for _, s := range metric.GetSamples() {
+				if seen, ok := expected[s.Metric.Name]; ok && !seen {
+					expected[s.Metric.Name] = true
+					count++
+					typ, _ := s.Tags.Get("navigation_type")
+					navigationTypes <- typ
+				}
+			}
+			if count == len(expected) {
+				cancel()
+			}
+		}
+	}()
+
+	resp, err := page.Goto(browser.staticURL("/web_vitals.html"), nil)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	<-ctx.Done()
+
+	for k, v := range expected {
+		assert.True(t, v, "expected %s to have been measured and emitted", k)
+	}
+	for i := 0; i < count; i++ {
+		assert.Equal(t, "navigate", <-navigationTypes)
+	}
+}
+
+// TestUserTimingMetric is asserting that the user timing
+// measures are emitted when the browser context opts in.
+func TestUserTimingMetric(t *testing.T) {
+	var (
+		samples  = make(chan k6metrics.SampleContainer)
+		browser  = newTestBrowser(t, withFileServer(), withSamplesListener(samples))
+		measures = make(chan string, 1)
+	)
+	bctx, err := browser.NewContext(browser.toGojaValue(map[string]any{"userTimings": true}))
+	require.NoError(t, err)
+	page, err := bctx.NewPage()
+	require.NoError(t, err)
+
+	go func() {
+		for metric := range samples {
+			for _, s := range metric.GetSamples() {
+				if s.Metric.Name != "browser_user_timing" {
+					continue
+				}
+				measure, _ := s.Tags.Get("measure")
+				measures <- measure
+				return
+			}
+		}
+	}()
+
+	_, err = page.Goto(browser.staticURL("/web_vitals.html"), nil)
+	require.NoError(t, err)
+	page.Evaluate(browser.toGojaValue(`() => {
+		performance.mark('start');
+		performance.mark('end');
+		performance.measure('checkout', 'start', 'end');
+	}`))
+
+	select {
+	case measure := <-measures:
+		assert.Equal(t, "checkout", measure)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the user timing metric")
+	}
+}
+
+// TestLongTaskMetrics is asserting that the long task and the
+// total blocking time metrics are emitted when the main thread
+// of a page is blocked.
+func TestLongTaskMetrics(t *testing.T) {
+	var (
+		samples  = make(chan k6metrics.SampleContainer)
+		browser  = newTestBrowser(t, withHTTPServer(), withSamplesListener(samples))
+		page     = browser.NewPage(nil)
+		expected = map[string]bool{
+			"browser_long_tasks":         false,
+			"browser_long_task_duration": false,
+			"browser_tbt":                false,
+		}
+	)
+	browser.withHandler("/blocking", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprint(w, `<html><body><div>blocking</div><script>
+			const end = Date.now() + 150;
+			while (Date.now() < end) {}
+		</script></body></html>`)
+	})
+
+	count := 0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	go func() {
+		for {
+			metric := <-samples
+			for _, s := range metric.GetSamples() {
+				if seen, ok := expected[s.Metric.Name]; ok && !seen {
+					expected[s.Metric.Name] = true
+					count++
+				}
+			}
+			if count == len(expected) {
+				cancel()
+			}
+		}
+	}()
+
+	_, err := page.Goto(browser.URL("/blocking"), nil)
+	require.NoError(t, err)
+
+	<-ctx.Done()
+
+	for k, v := range expected {
+		assert.True(t, v, "expected %s to have been measured and emitted", k)
+	}
+}